@@ -0,0 +1,161 @@
+// Package assign - Pemilihan dokter & jam otomatis untuk appointment pending per poli
+package assign
+
+import (
+	"database/sql"
+	"errors"
+	"klinik-app/booking"
+	"klinik-app/config"
+	"klinik-app/models"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Strategi pemilihan dokter (env AUTO_ASSIGN_STRATEGY)
+const (
+	LeastLoaded    = "least_loaded"
+	RoundRobin     = "round_robin"
+	PreviousDoctor = "previous_doctor"
+)
+
+// Choice - Dokter & jam yang dipilih untuk appointment
+type Choice struct {
+	DoctorID int
+	Nama     string
+	Waktu    string // HH:MM
+}
+
+// kandidat - Dokter yang praktik pada tanggal tersebut beserta beban & jam kosongnya
+type kandidat struct {
+	models.DoctorOnDuty
+	load  int
+	slots []string
+}
+
+// mu - Auto-assign dijalankan satu per satu supaya dua appointment tidak mendapat slot yang sama
+var mu sync.Mutex
+
+// candidates - Dokter poli yang praktik pada tanggal appointment dan masih punya jam kosong
+func candidates(db *sql.DB, p config.BookingPolicy, apt *models.Appointment, now time.Time) ([]kandidat, error) {
+	tanggal := apt.TanggalKonsultasi.Format("2006-01-02")
+	doctors, err := models.GetDoctorsOnDuty(db, int(apt.PoliID.Int64), tanggal)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []kandidat
+	for _, d := range doctors {
+		booked, err := models.GetDoctorBookedTimes(db, d.UserID, tanggal, apt.AppointmentID)
+		if err != nil {
+			return nil, err
+		}
+		free, err := booking.AvailableSlots(db, p, d.UserID, tanggal, apt.AppointmentID, now)
+		if err != nil {
+			return nil, err
+		}
+
+		// Hanya jam di dalam jadwal praktik dokter
+		var slots []string
+		for _, s := range free {
+			if s >= d.JamMulai && s < d.JamSelesai {
+				slots = append(slots, s)
+			}
+		}
+		if len(slots) > 0 {
+			list = append(list, kandidat{DoctorOnDuty: d, load: len(booked), slots: slots})
+		}
+	}
+
+	// Urutan dasar: beban paling sedikit, lalu ID dokter supaya hasilnya stabil
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].load != list[j].load {
+			return list[i].load < list[j].load
+		}
+		return list[i].UserID < list[j].UserID
+	})
+	return list, nil
+}
+
+// Suggest - Dokter & jam pertama yang kosong menurut strategi; nil jika appointment tanpa poli atau tidak ada dokter tersedia
+func Suggest(db *sql.DB, p config.BookingPolicy, strategy string, apt *models.Appointment, now time.Time) (*Choice, error) {
+	if !apt.PoliID.Valid {
+		return nil, nil
+	}
+
+	list, err := candidates(db, p, apt, now)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+
+	pick := list[0] // least_loaded
+	switch strategy {
+	case RoundRobin:
+		last, err := models.LastAssignedDoctor(db, int(apt.PoliID.Int64))
+		if err != nil {
+			return nil, err
+		}
+		// Dokter dengan ID berikutnya setelah yang terakhir mendapat appointment, berputar ke awal
+		sort.Slice(list, func(i, j int) bool { return list[i].UserID < list[j].UserID })
+		pick = list[0]
+		for _, k := range list {
+			if k.UserID > last {
+				pick = k
+				break
+			}
+		}
+	case PreviousDoctor:
+		prev, err := models.PreviousDoctor(db, apt.PatientID)
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range list {
+			if k.UserID == prev {
+				pick = k
+			}
+		}
+	}
+
+	return &Choice{DoctorID: pick.UserID, Nama: pick.Nama, Waktu: pick.slots[0]}, nil
+}
+
+// AutoAssign - Beri dokter & jam untuk appointment pending jika AUTO_ASSIGN aktif; admin tetap bisa mengubahnya lewat reschedule
+func AutoAssign(appointmentID int) error {
+	if !config.AutoAssignEnabled() {
+		return nil
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	apt, err := models.GetAppointmentByID(config.DB, appointmentID)
+	if err != nil {
+		return err
+	}
+	if apt.Status != "pending" {
+		return nil
+	}
+
+	// Slot bisa terisi appointment lain (approve admin, reschedule) sejak Suggest; pilih ulang beberapa kali
+	for attempt := 0; attempt < 3; attempt++ {
+		choice, err := Suggest(config.DB, config.Booking(), config.AutoAssignStrategy(), apt, config.Now())
+		if err != nil || choice == nil {
+			return err // tanpa dokter tersedia appointment tetap pending untuk admin
+		}
+
+		ok, err := models.AutoApproveAppointment(config.DB, appointmentID, choice.DoctorID,
+			apt.TanggalKonsultasi.Format("2006-01-02"), choice.Waktu)
+		if errors.Is(err, models.ErrSlotTaken) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if ok {
+			log.Printf("✓ Appointment %d auto-assigned to %s at %s", appointmentID, choice.Nama, choice.Waktu)
+		}
+		return nil
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+// Package booking - Validasi aturan booking pasien (lead time, batas harian, hari libur)
+package booking
+
+import (
+	"database/sql"
+	"fmt"
+	"klinik-app/config"
+	"klinik-app/models"
+	"strings"
+	"time"
+)
+
+// RuleError - Pelanggaran aturan booking; pesannya aman ditampilkan ke pasien
+type RuleError struct {
+	Message string
+}
+
+func (e *RuleError) Error() string {
+	return e.Message
+}
+
+func ruleError(format string, args ...interface{}) error {
+	return &RuleError{Message: fmt.Sprintf(format, args...)}
+}
+
+var namaHari = map[time.Weekday]string{
+	time.Sunday:    "Minggu",
+	time.Monday:    "Senin",
+	time.Tuesday:   "Selasa",
+	time.Wednesday: "Rabu",
+	time.Thursday:  "Kamis",
+	time.Friday:    "Jumat",
+	time.Saturday:  "Sabtu",
+}
+
+// ClosedDaysLabel - Nama hari tutup untuk ditampilkan, misal "Sabtu, Minggu"
+func ClosedDaysLabel(p config.BookingPolicy) string {
+	var names []string
+	for _, day := range p.ClosedDays {
+		names = append(names, namaHari[day])
+	}
+	return strings.Join(names, ", ")
+}
+
+// DateRange - Tanggal paling awal & paling akhir yang boleh dipilih (YYYY-MM-DD), untuk atribut min/max form
+func DateRange(p config.BookingPolicy, today time.Time) (string, string) {
+	return today.AddDate(0, 0, p.MinLeadDays).Format("2006-01-02"),
+		today.AddDate(0, 0, p.MaxLeadDays).Format("2006-01-02")
+}
+
+// CheckDate - Validasi tanggal: format, tidak lewat, dalam rentang lead time, klinik buka dan bukan hari libur
+func CheckDate(db *sql.DB, p config.BookingPolicy, tanggal string, today time.Time) error {
+	if err := checkRange(p, tanggal, today); err != nil {
+		return err
+	}
+	return CheckOpen(db, p, tanggal)
+}
+
+// checkRange - Format tanggal, tidak lewat, dan dalam rentang lead time dihitung dari today (waktu klinik)
+func checkRange(p config.BookingPolicy, tanggal string, today time.Time) error {
+	t, err := time.Parse("2006-01-02", tanggal)
+	if err != nil {
+		return ruleError("Tanggal konsultasi tidak valid")
+	}
+
+	minDate, maxDate := DateRange(p, today)
+	switch {
+	case tanggal < today.Format("2006-01-02"):
+		return ruleError("Tanggal %s sudah lewat, silakan pilih tanggal lain", t.Format("02/01/2006"))
+	case tanggal < minDate:
+		return ruleError("Booking paling cepat %d hari sebelum tanggal konsultasi", p.MinLeadDays)
+	case tanggal > maxDate:
+		return ruleError("Booking paling jauh %d hari ke depan", p.MaxLeadDays)
+	}
+	return nil
+}
+
+// CheckOpen - Klinik buka pada tanggal tersebut: bukan hari tutup mingguan dan bukan hari libur
+func CheckOpen(db *sql.DB, p config.BookingPolicy, tanggal string) error {
+	t, err := time.Parse("2006-01-02", tanggal)
+	if err != nil {
+		return ruleError("Tanggal konsultasi tidak valid")
+	}
+
+	for _, day := range p.ClosedDays {
+		if t.Weekday() == day {
+			return ruleError("Klinik tutup setiap hari %s, silakan pilih hari lain", namaHari[day])
+		}
+	}
+
+	holiday, err := models.GetHoliday(db, tanggal)
+	if err == nil {
+		return ruleError("Klinik libur pada %s (%s), silakan pilih tanggal lain",
+			t.Format("02/01/2006"), holiday.Keterangan)
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	return nil
+}
+
+// Validate - Semua aturan booking untuk pasien: tanggal, jumlah booking aktif, dan kapasitas harian
+func Validate(db *sql.DB, p config.BookingPolicy, patientID int, tanggal string, today time.Time) error {
+	if err := CheckDate(db, p, tanggal, today); err != nil {
+		return err
+	}
+	return checkQuota(db, p, patientID, tanggal, today)
+}
+
+// Book - Simpan appointment baru; aturan kuota diperiksa ulang di dalam transaksi yang mengunci pasien & tanggal
+func Book(db *sql.DB, p config.BookingPolicy, nomorReg string, patientID, poliID int, tanggal string, today time.Time) (int, error) {
+	return models.CreateAppointment(db, nomorReg, patientID, poliID, tanggal, func(tx *sql.Tx) error {
+		return checkQuota(tx, p, patientID, tanggal, today)
+	})
+}
+
+// Claim - Klaim tawaran slot daftar tunggu; aturan kuota sama dengan booking baru dan diperiksa di dalam transaksi
+func Claim(db *sql.DB, p config.BookingPolicy, offer *models.WaitlistOffer, nomorReg string, today time.Time) (int, error) {
+	return models.ClaimWaitlistOffer(db, offer, nomorReg, func(tx *sql.Tx) error {
+		return checkQuota(tx, p, offer.PatientID, offer.Tanggal.Format("2006-01-02"), today)
+	})
+}
+
+// checkQuota - Aturan per pasien & kuota harian: no-show, batas booking aktif, satu appointment per tanggal
+func checkQuota(q models.Querier, p config.BookingPolicy, patientID int, tanggal string, today time.Time) error {
+	if p.NoShowLimit > 0 {
+		noShows, err := models.CountNoShows(q, patientID, p.NoShowWindowDays, today.Format("2006-01-02"))
+		if err != nil {
+			return err
+		}
+		if noShows >= p.NoShowLimit {
+			return ruleError("Booking online dinonaktifkan karena Anda %d kali tidak hadir dalam %d hari terakhir. "+
+				"Silakan hubungi klinik untuk membuat jadwal", noShows, p.NoShowWindowDays)
+		}
+	}
+
+	if p.MaxActive > 0 {
+		active, err := models.CountActiveAppointments(q, patientID)
+		if err != nil {
+			return err
+		}
+		if active >= p.MaxActive {
+			return ruleError("Anda sudah memiliki %d appointment aktif (maksimal %d). "+
+				"Selesaikan atau batalkan salah satunya terlebih dahulu", active, p.MaxActive)
+		}
+	}
+
+	exists, err := models.HasAppointmentOnDate(q, patientID, tanggal)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ruleError("Anda sudah memiliki appointment pada tanggal tersebut")
+	}
+
+	if p.DailyCapacity > 0 {
+		count, err := models.CountAppointmentsOnDate(q, tanggal)
+		if err != nil {
+			return err
+		}
+		if count >= p.DailyCapacity {
+			return ruleError("Kuota konsultasi pada tanggal tersebut sudah penuh. " +
+				"Silakan pilih tanggal lain atau masuk daftar tunggu")
+		}
+	}
+
+	return nil
+}
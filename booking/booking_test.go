@@ -0,0 +1,76 @@
+package booking
+
+import (
+	"errors"
+	"klinik-app/config"
+	"testing"
+	"time"
+)
+
+func jakarta(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+// 00:30 WIB tanggal 19 = 17:30 UTC tanggal 18
+var tengahMalam = time.Date(2026, 10, 18, 17, 30, 0, 0, time.UTC)
+
+func TestDateRange(t *testing.T) {
+	loc := jakarta(t)
+
+	tests := []struct {
+		name             string
+		now              time.Time
+		min, max         int
+		wantMin, wantMax string
+	}{
+		{"00:30 WIB sudah hari baru", tengahMalam.In(loc), 0, 30, "2026-10-19", "2026-11-18"},
+		{"lead time 1 hari", tengahMalam.In(loc), 1, 7, "2026-10-20", "2026-10-26"},
+		{"23:59 WIB masih hari sebelumnya", time.Date(2026, 10, 18, 16, 59, 0, 0, time.UTC).In(loc), 0, 1,
+			"2026-10-18", "2026-10-19"},
+		{"00:00 WIB 1 Januari sudah tahun baru", time.Date(2026, 12, 31, 17, 0, 0, 0, time.UTC).In(loc), 0, 30,
+			"2027-01-01", "2027-01-31"},
+	}
+
+	for _, tt := range tests {
+		p := config.BookingPolicy{MinLeadDays: tt.min, MaxLeadDays: tt.max}
+		gotMin, gotMax := DateRange(p, tt.now)
+		if gotMin != tt.wantMin || gotMax != tt.wantMax {
+			t.Errorf("%s: DateRange = %s..%s, want %s..%s", tt.name, gotMin, gotMax, tt.wantMin, tt.wantMax)
+		}
+	}
+}
+
+func TestCheckRange(t *testing.T) {
+	now := tengahMalam.In(jakarta(t))
+
+	tests := []struct {
+		name    string
+		policy  config.BookingPolicy
+		tanggal string
+		wantErr bool
+	}{
+		{"hari ini menurut klinik", config.BookingPolicy{MaxLeadDays: 30}, "2026-10-19", false},
+		{"kemarin menurut klinik (hari ini menurut UTC)", config.BookingPolicy{MaxLeadDays: 30}, "2026-10-18", true},
+		{"lead time 1 hari: hari ini ditolak", config.BookingPolicy{MinLeadDays: 1, MaxLeadDays: 30}, "2026-10-19", true},
+		{"lead time 1 hari: besok boleh", config.BookingPolicy{MinLeadDays: 1, MaxLeadDays: 30}, "2026-10-20", false},
+		{"batas terjauh", config.BookingPolicy{MaxLeadDays: 30}, "2026-11-18", false},
+		{"lewat batas terjauh", config.BookingPolicy{MaxLeadDays: 30}, "2026-11-19", true},
+		{"format salah", config.BookingPolicy{MaxLeadDays: 30}, "19/10/2026", true},
+	}
+
+	for _, tt := range tests {
+		err := checkRange(tt.policy, tt.tanggal, now)
+		var ruleErr *RuleError
+		if err != nil && !errors.As(err, &ruleErr) {
+			t.Errorf("%s: error %v is not a RuleError", tt.name, err)
+		}
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: checkRange(%s) = %v, wantErr %v", tt.name, tt.tanggal, err, tt.wantErr)
+		}
+	}
+}
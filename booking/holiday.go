@@ -0,0 +1,76 @@
+package booking
+
+import (
+	"bufio"
+	"database/sql"
+	"fmt"
+	"io"
+	"klinik-app/models"
+	"os"
+	"strings"
+	"time"
+)
+
+// ParseHolidays - Baca daftar hari libur dari CSV "YYYY-MM-DD,Keterangan" (pemisah koma atau titik koma).
+// Baris kosong (termasuk baris berisi pemisah saja dari ekspor spreadsheet), komentar (#) dan header
+// yang bukan tanggal dilewati.
+func ParseHolidays(r io.Reader) ([]models.Holiday, error) {
+	var holidays []models.Holiday
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		parts := strings.FieldsFunc(text, func(c rune) bool { return c == ',' || c == ';' })
+		if len(parts) == 0 {
+			continue
+		}
+		tanggal := strings.Trim(strings.TrimSpace(parts[0]), `"`)
+		if _, err := time.Parse("2006-01-02", tanggal); err != nil {
+			if len(holidays) == 0 && line == 1 {
+				continue // header
+			}
+			return nil, fmt.Errorf("baris %d: tanggal %q harus berformat YYYY-MM-DD", line, tanggal)
+		}
+
+		keterangan := "Hari libur"
+		if len(parts) > 1 {
+			keterangan = strings.Trim(strings.TrimSpace(strings.Join(parts[1:], ",")), `"`)
+		}
+		holidays = append(holidays, models.Holiday{Tanggal: tanggal, Keterangan: keterangan})
+	}
+
+	return holidays, scanner.Err()
+}
+
+// ImportHolidays - Simpan semua hari libur dari file CSV, kembalikan jumlah baris yang diimport
+func ImportHolidays(db *sql.DB, r io.Reader) (int, error) {
+	holidays, err := ParseHolidays(r)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, h := range holidays {
+		if err := models.SaveHoliday(db, h.Tanggal, h.Keterangan); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(holidays), nil
+}
+
+// ImportHolidaysFile - Import hari libur dari path file (dipakai saat startup dengan HOLIDAYS_FILE)
+func ImportHolidaysFile(db *sql.DB, path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	return ImportHolidays(db, f)
+}
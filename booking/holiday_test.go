@@ -0,0 +1,59 @@
+package booking
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseHolidays(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string // tanggal|keterangan
+		wantErr bool
+	}{
+		{
+			name:  "header dan komentar dilewati",
+			input: "tanggal,keterangan\n# libur nasional\n2026-12-25,Natal\n",
+			want:  []string{"2026-12-25|Natal"},
+		},
+		{
+			name:  "titik koma dan tanpa keterangan",
+			input: "2026-08-17;Kemerdekaan\n2026-01-01\n",
+			want:  []string{"2026-08-17|Kemerdekaan", "2026-01-01|Hari libur"},
+		},
+		{
+			name:  "baris pemisah saja dari ekspor spreadsheet",
+			input: "2026-12-25,Natal\n,\n;;\n,,,\n",
+			want:  []string{"2026-12-25|Natal"},
+		},
+		{
+			name:    "tanggal tidak valid",
+			input:   "2026-12-25,Natal\n25/12/2026,Natal\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			holidays, err := ParseHolidays(strings.NewReader(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", holidays)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, h := range holidays {
+				got = append(got, h.Tanggal+"|"+h.Keterangan)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
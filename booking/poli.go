@@ -0,0 +1,41 @@
+package booking
+
+import (
+	"database/sql"
+	"klinik-app/models"
+	"time"
+)
+
+// NamaHari - Nama hari dalam bahasa Indonesia, misal "Senin"
+func NamaHari(day time.Weekday) string {
+	return namaHari[day]
+}
+
+// CheckPoli - Poli harus aktif dan (jika jadwal dokternya sudah diatur) ada dokter yang praktik pada tanggal tersebut
+func CheckPoli(db *sql.DB, poliID int, tanggal string) error {
+	poli, err := models.GetPoli(db, poliID)
+	if err == sql.ErrNoRows || (err == nil && !poli.Aktif) {
+		return ruleError("Silakan pilih poli tujuan")
+	}
+	if err != nil {
+		return err
+	}
+
+	// Poli yang belum punya jadwal dokter sama sekali tidak dibatasi, admin yang menentukan dokternya
+	scheduled, err := models.PoliHasSchedules(db, poliID)
+	if err != nil || !scheduled {
+		return err
+	}
+
+	doctors, err := models.GetDoctorsOnDuty(db, poliID, tanggal)
+	if err != nil {
+		return err
+	}
+	if len(doctors) == 0 {
+		t, _ := time.Parse("2006-01-02", tanggal)
+		return ruleError("Tidak ada dokter %s yang praktik pada hari %s, silakan pilih tanggal lain",
+			poli.Nama, namaHari[t.Weekday()])
+	}
+
+	return nil
+}
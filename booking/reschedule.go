@@ -0,0 +1,135 @@
+package booking
+
+import (
+	"database/sql"
+	"errors"
+	"klinik-app/config"
+	"klinik-app/models"
+	"time"
+)
+
+// Slots - Semua jam praktik (HH:MM) dari jam buka sampai tutup, selang CONSULTATION_MINUTES
+func Slots(p config.BookingPolicy) []string {
+	step := config.ConsultationDuration()
+	var slots []string
+	for t := p.OpenTime; t+step <= p.CloseTime; t += step {
+		slots = append(slots, time.Time{}.Add(t).Format("15:04"))
+	}
+	return slots
+}
+
+// AvailableSlots - Jam kosong dokter pada tanggal tersebut (jam yang sudah lewat hari ini tidak ditampilkan)
+func AvailableSlots(db *sql.DB, p config.BookingPolicy, doctorID int, tanggal string, excludeAppointmentID int, now time.Time) ([]string, error) {
+	booked, err := models.GetDoctorBookedTimes(db, doctorID, tanggal, excludeAppointmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var slots []string
+	for _, s := range Slots(p) {
+		if booked[s] {
+			continue
+		}
+		if tanggal == now.Format("2006-01-02") && s <= now.Format("15:04") {
+			continue
+		}
+		slots = append(slots, s)
+	}
+	return slots, nil
+}
+
+// jadwalAcuan - Jam appointment; jika belum ditentukan dipakai jam buka klinik pada tanggalnya
+func jadwalAcuan(p config.BookingPolicy, apt *models.Appointment, loc *time.Location) time.Time {
+	if start, ok := apt.JadwalMulai(loc); ok {
+		return start
+	}
+	d := apt.TanggalKonsultasi
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc).Add(p.OpenTime)
+}
+
+// CanReschedule - Cek apakah pasien masih boleh mengubah jadwal appointment ini
+func CanReschedule(p config.BookingPolicy, apt *models.Appointment, now time.Time) error {
+	if apt.Status != "pending" && apt.Status != "approved" {
+		return ruleError("Jadwal hanya bisa diubah untuk appointment yang masih pending atau approved")
+	}
+	if apt.RescheduleCount >= p.MaxReschedule {
+		return ruleError("Jadwal appointment ini sudah diubah %d kali (maksimal %d). "+
+			"Silakan hubungi klinik jika perlu perubahan lagi", apt.RescheduleCount, p.MaxReschedule)
+	}
+
+	start := jadwalAcuan(p, apt, now.Location())
+	if start.Sub(now) < time.Duration(p.RescheduleMinHours)*time.Hour {
+		return ruleError("Perubahan jadwal paling lambat %d jam sebelum jadwal konsultasi", p.RescheduleMinHours)
+	}
+
+	return nil
+}
+
+// ValidateReschedule - Validasi tanggal baru untuk perubahan jadwal oleh pasien. Kuota tanggal tujuan diperiksa
+// oleh Reschedule di dalam transaksi.
+func ValidateReschedule(db *sql.DB, p config.BookingPolicy, apt *models.Appointment, tanggal string, today time.Time) error {
+	if err := CanReschedule(p, apt, today); err != nil {
+		return err
+	}
+	return CheckDate(db, p, tanggal, today)
+}
+
+// Reschedule - Simpan perubahan jadwal oleh pasien: ke jam waktu dokter yang sama, atau kembali ke pending jika
+// waktu kosong. Kuota tanggal tujuan diperiksa di dalam transaksi yang mengunci tanggal & pasien.
+func Reschedule(db *sql.DB, p config.BookingPolicy, apt *models.Appointment, tanggal, waktu string) error {
+	check := func(tx *sql.Tx) error {
+		return checkMoveQuota(tx, p, apt.PatientID, apt.TanggalKonsultasi.Format("2006-01-02"), tanggal)
+	}
+	if waktu == "" {
+		return models.RescheduleToPending(db, apt.AppointmentID, apt.PatientID, p.MaxReschedule, tanggal, check)
+	}
+	return models.RescheduleToSlot(db, apt.AppointmentID, apt.PatientID, int(apt.DoctorID.Int64),
+		p.MaxReschedule, tanggal, waktu, check)
+}
+
+// AdminReschedule - Ubah jadwal satu appointment oleh admin dengan kuota tanggal tujuan yang sama dengan BulkReschedule
+func AdminReschedule(db *sql.DB, p config.BookingPolicy, appointmentID, doctorID int, tanggal, waktu string) error {
+	return models.RescheduleAppointment(db, appointmentID, doctorID, tanggal, waktu, func(tx *sql.Tx, patientID int, dari string) error {
+		return checkMoveQuota(tx, p, patientID, dari, tanggal)
+	})
+}
+
+// BulkReschedule - Pindah tanggal massal oleh admin; appointment yang melanggar kuota tanggal tujuan dilewati
+func BulkReschedule(db *sql.DB, p config.BookingPolicy, ids []int, tanggal string) ([]models.BulkResult, error) {
+	return models.BulkReschedule(db, ids, tanggal, func(tx *sql.Tx, patientID int, dari string) (string, error) {
+		err := checkMoveQuota(tx, p, patientID, dari, tanggal)
+		var ruleErr *RuleError
+		if errors.As(err, &ruleErr) {
+			return ruleErr.Message, nil
+		}
+		return "", err
+	})
+}
+
+// checkMoveQuota - Kuota tanggal tujuan saat appointment dipindah dari tanggal dari: satu appointment per pasien
+// per tanggal dan kapasitas harian (tidak diperiksa jika tanggalnya tetap)
+func checkMoveQuota(q models.Querier, p config.BookingPolicy, patientID int, dari, tanggal string) error {
+	if tanggal == dari {
+		return nil
+	}
+
+	exists, err := models.HasAppointmentOnDate(q, patientID, tanggal)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ruleError("Sudah ada appointment lain milik pasien pada tanggal tersebut")
+	}
+
+	if p.DailyCapacity > 0 {
+		count, err := models.CountAppointmentsOnDate(q, tanggal)
+		if err != nil {
+			return err
+		}
+		if count >= p.DailyCapacity {
+			return ruleError("Kuota konsultasi pada tanggal tersebut sudah penuh, silakan pilih tanggal lain")
+		}
+	}
+
+	return nil
+}
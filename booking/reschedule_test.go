@@ -0,0 +1,59 @@
+package booking
+
+import (
+	"database/sql"
+	"klinik-app/config"
+	"klinik-app/models"
+	"testing"
+	"time"
+)
+
+func TestCanReschedule(t *testing.T) {
+	loc := jakarta(t)
+	p := config.BookingPolicy{MaxReschedule: 2, RescheduleMinHours: 24, OpenTime: 8 * time.Hour}
+
+	// Tanggal dari DB (parseTime dengan loc klinik) berupa tengah malam waktu klinik
+	tanggal := func(s string) time.Time {
+		d, _ := time.ParseInLocation("2006-01-02", s, loc)
+		return d
+	}
+	jam := func(s string) sql.NullString { return sql.NullString{String: s, Valid: s != ""} }
+
+	tests := []struct {
+		name    string
+		apt     models.Appointment
+		now     time.Time
+		wantErr bool
+	}{
+		{"approved, 24 jam lebih 1 menit sebelum jadwal",
+			models.Appointment{Status: "approved", TanggalKonsultasi: tanggal("2026-10-20"), WaktuKonsultasi: jam("09:00:00")},
+			time.Date(2026, 10, 19, 1, 59, 0, 0, time.UTC), false},
+		{"approved, kurang dari 24 jam sebelum jadwal",
+			models.Appointment{Status: "approved", TanggalKonsultasi: tanggal("2026-10-20"), WaktuKonsultasi: jam("09:00:00")},
+			time.Date(2026, 10, 19, 2, 1, 0, 0, time.UTC), true},
+		{"pending 00:30 WIB, jadwal acuan jam buka hari ini",
+			models.Appointment{Status: "pending", TanggalKonsultasi: tanggal("2026-10-19")},
+			tengahMalam, true},
+		{"pending 00:30 WIB, jadwal besok masih lebih dari 24 jam",
+			models.Appointment{Status: "pending", TanggalKonsultasi: tanggal("2026-10-20")},
+			tengahMalam, false},
+		{"tanggal dari driver dalam UTC tetap dibaca sebagai tanggal klinik",
+			models.Appointment{Status: "pending", TanggalKonsultasi: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)},
+			tengahMalam, false},
+		{"batas perubahan tercapai",
+			models.Appointment{Status: "approved", TanggalKonsultasi: tanggal("2026-10-25"), WaktuKonsultasi: jam("09:00"),
+				RescheduleCount: 2},
+			tengahMalam, true},
+		{"status completed",
+			models.Appointment{Status: "completed", TanggalKonsultasi: tanggal("2026-10-25")},
+			tengahMalam, true},
+	}
+
+	for _, tt := range tests {
+		apt := tt.apt
+		err := CanReschedule(p, &apt, tt.now.In(loc))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: CanReschedule = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
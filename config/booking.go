@@ -0,0 +1,97 @@
+package config
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BookingPolicy - Aturan booking pasien (diatur lewat environment variable)
+type BookingPolicy struct {
+	MinLeadDays   int            // minimal berapa hari sebelum tanggal konsultasi (0 = boleh hari ini)
+	MaxLeadDays   int            // maksimal berapa hari ke depan
+	MaxActive     int            // maksimal appointment aktif (pending/approved) per pasien, 0 = tanpa batas
+	DailyCapacity int            // maksimal appointment klinik per hari, 0 = tanpa batas
+	ClosedDays    []time.Weekday // hari klinik tutup
+	HolidaysFile  string         // file CSV hari libur yang diimport saat startup (opsional)
+
+	NoShowLimit      int // booking diblokir setelah sekian kali tidak hadir, 0 = tidak dibatasi
+	NoShowWindowDays int // ketidakhadiran dihitung dalam sekian hari terakhir
+
+	MaxReschedule      int // berapa kali pasien boleh mengubah jadwal sendiri per appointment
+	RescheduleMinHours int // perubahan jadwal ditutup sekian jam sebelum jadwal
+
+	OpenTime  time.Duration // jam buka praktik (sejak tengah malam)
+	CloseTime time.Duration // jam tutup praktik
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "minggu": time.Sunday,
+	"monday": time.Monday, "senin": time.Monday,
+	"tuesday": time.Tuesday, "selasa": time.Tuesday,
+	"wednesday": time.Wednesday, "rabu": time.Wednesday,
+	"thursday": time.Thursday, "kamis": time.Thursday,
+	"friday": time.Friday, "jumat": time.Friday,
+	"saturday": time.Saturday, "sabtu": time.Saturday,
+}
+
+// getEnvInt - Environment variable angka >= 0 dengan nilai default
+func getEnvInt(key string, fallback int) int {
+	v := getEnv(key, "")
+	if v == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		log.Printf("⚠️ Invalid %s, using %d", key, fallback)
+		return fallback
+	}
+	return n
+}
+
+// clockEnv - Jam "HH:MM" dari environment variable sebagai durasi sejak tengah malam
+func clockEnv(key, fallback string) time.Duration {
+	t, err := time.Parse("15:04", getEnv(key, fallback))
+	if err != nil {
+		log.Printf("⚠️ Invalid %s, using %s", key, fallback)
+		t, _ = time.Parse("15:04", fallback)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+}
+
+// Booking - Aturan booking dari env BOOKING_MIN_LEAD_DAYS (0), BOOKING_MAX_LEAD_DAYS (60),
+// BOOKING_MAX_ACTIVE (3), CLINIC_DAILY_CAPACITY (0), CLINIC_CLOSED_DAYS ("minggu"), HOLIDAYS_FILE,
+// NO_SHOW_LIMIT (0), NO_SHOW_WINDOW_DAYS (180), RESCHEDULE_MAX (2), RESCHEDULE_MIN_HOURS (24),
+// CLINIC_OPEN ("08:00") dan CLINIC_CLOSE ("16:00")
+func Booking() BookingPolicy {
+	p := BookingPolicy{
+		MinLeadDays:        getEnvInt("BOOKING_MIN_LEAD_DAYS", 0),
+		MaxLeadDays:        getEnvInt("BOOKING_MAX_LEAD_DAYS", 60),
+		MaxActive:          getEnvInt("BOOKING_MAX_ACTIVE", 3),
+		DailyCapacity:      getEnvInt("CLINIC_DAILY_CAPACITY", 0),
+		HolidaysFile:       getEnv("HOLIDAYS_FILE", ""),
+		NoShowLimit:        getEnvInt("NO_SHOW_LIMIT", 0),
+		NoShowWindowDays:   getEnvInt("NO_SHOW_WINDOW_DAYS", 180),
+		MaxReschedule:      getEnvInt("RESCHEDULE_MAX", 2),
+		RescheduleMinHours: getEnvInt("RESCHEDULE_MIN_HOURS", 24),
+		OpenTime:           clockEnv("CLINIC_OPEN", "08:00"),
+		CloseTime:          clockEnv("CLINIC_CLOSE", "16:00"),
+	}
+
+	for _, name := range strings.Split(getEnv("CLINIC_CLOSED_DAYS", "minggu"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || name == "-" {
+			continue
+		}
+		day, ok := weekdayNames[name]
+		if !ok {
+			log.Printf("⚠️ Invalid CLINIC_CLOSED_DAYS entry: %q", name)
+			continue
+		}
+		p.ClosedDays = append(p.ClosedDays, day)
+	}
+
+	return p
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"klinik-app/regno"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata" // zona waktu tetap bisa dimuat di container tanpa paket tzdata
+)
+
+// getEnv - Ambil environment variable dengan nilai default
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// ClinicName - Nama klinik untuk kop dokumen
+func ClinicName() string {
+	return getEnv("CLINIC_NAME", "Klinik Sehat")
+}
+
+// ClinicAddress - Alamat klinik untuk kop dokumen
+func ClinicAddress() string {
+	return getEnv("CLINIC_ADDRESS", "")
+}
+
+// BaseURL - URL publik aplikasi (tanpa "/" di akhir), dipakai untuk link di dokumen & notifikasi
+func BaseURL() string {
+	return strings.TrimSuffix(getEnv("APP_URL", ""), "/")
+}
+
+var (
+	locationOnce sync.Once
+	location     *time.Location
+)
+
+// Location - Zona waktu klinik dari env CLINIC_TIMEZONE (default Asia/Jakarta).
+// "Hari ini" dan jam konsultasi dihitung di zona ini, bukan zona server aplikasi atau DB.
+func Location() *time.Location {
+	locationOnce.Do(func() {
+		name := getEnv("CLINIC_TIMEZONE", "Asia/Jakarta")
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			log.Printf("⚠️ Invalid CLINIC_TIMEZONE %q, using server local time: %v", name, err)
+			loc = time.Local
+		}
+		location = loc
+	})
+	return location
+}
+
+// Now - Waktu sekarang di zona waktu klinik
+func Now() time.Time {
+	return time.Now().In(Location())
+}
+
+// RegistrationFormat - Format nomor registrasi dari env NOMOR_REG_FORMAT (default KLN-{YYYY}{MM}{DD}-{SEQ}{CHECK})
+func RegistrationFormat() string {
+	format := getEnv("NOMOR_REG_FORMAT", regno.DefaultFormat)
+	if !regno.ValidFormat(format) {
+		log.Printf("⚠️ Invalid NOMOR_REG_FORMAT %q (needs date, {SEQ} and {CHECK}), using default", format)
+		return regno.DefaultFormat
+	}
+	return format
+}
+
+// ReminderOffsets - Kapan pengingat dikirim sebelum jadwal, dari env REMINDER_OFFSETS (default "24h,2h")
+func ReminderOffsets() []time.Duration {
+	var offsets []time.Duration
+	for _, s := range strings.Split(getEnv("REMINDER_OFFSETS", "24h,2h"), ",") {
+		d, err := time.ParseDuration(strings.TrimSpace(s))
+		if err != nil || d <= 0 {
+			log.Printf("⚠️ Invalid REMINDER_OFFSETS entry: %q", s)
+			continue
+		}
+		offsets = append(offsets, d)
+	}
+	return offsets
+}
+
+// ConsultationDuration - Perkiraan lama satu konsultasi dari env CONSULTATION_MINUTES (default 30 menit)
+func ConsultationDuration() time.Duration {
+	minutes, err := strconv.Atoi(getEnv("CONSULTATION_MINUTES", "30"))
+	if err != nil || minutes <= 0 {
+		log.Printf("⚠️ Invalid CONSULTATION_MINUTES, using 30")
+		minutes = 30
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// WaitlistClaimTTL - Batas waktu pasien waitlist mengklaim slot yang ditawarkan, dari env WAITLIST_CLAIM_TTL (default 2 jam)
+func WaitlistClaimTTL() time.Duration {
+	d, err := time.ParseDuration(getEnv("WAITLIST_CLAIM_TTL", "2h"))
+	if err != nil || d <= 0 {
+		log.Printf("⚠️ Invalid WAITLIST_CLAIM_TTL, using 2h")
+		d = 2 * time.Hour
+	}
+	return d
+}
+
+// AutoAssignEnabled - Appointment pending langsung diberi dokter & jam secara otomatis, dari env AUTO_ASSIGN=1
+func AutoAssignEnabled() bool {
+	return getEnv("AUTO_ASSIGN", "") == "1"
+}
+
+// AutoAssignStrategy - Cara memilih dokter: least_loaded (default), round_robin atau previous_doctor, dari env AUTO_ASSIGN_STRATEGY
+func AutoAssignStrategy() string {
+	switch s := getEnv("AUTO_ASSIGN_STRATEGY", "least_loaded"); s {
+	case "least_loaded", "round_robin", "previous_doctor":
+		return s
+	default:
+		log.Printf("⚠️ Invalid AUTO_ASSIGN_STRATEGY %q, using least_loaded", s)
+		return "least_loaded"
+	}
+}
+
+// CancelReasons - Pilihan alasan pembatalan dari env CANCEL_REASONS (dipisah koma); "Lainnya" selalu tersedia dengan keterangan
+func CancelReasons() []string {
+	var reasons []string
+	for _, s := range strings.Split(getEnv("CANCEL_REASONS",
+		"Pasien berhalangan,Sudah sembuh,Jadwal bentrok,Dokter berhalangan,Klinik tutup,Booking ganda"), ",") {
+		if s = strings.TrimSpace(s); s != "" && s != "Lainnya" {
+			reasons = append(reasons, s)
+		}
+	}
+	return reasons
+}
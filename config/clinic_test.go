@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Zona tidak valid membuat Location() kembali ke time.Local; zona lokal dipatok UTC supaya TestLocation
+// tidak bergantung pada zona mesin yang menjalankan test
+func TestMain(m *testing.M) {
+	os.Setenv("TZ", "UTC")
+	time.Local = time.UTC
+	os.Exit(m.Run())
+}
+
+func TestLocation(t *testing.T) {
+	tests := []struct {
+		env  string
+		want string
+	}{
+		{"", "Asia/Jakarta"},
+		{"Asia/Makassar", "Asia/Makassar"},
+		{"Asia/Jayapura", "Asia/Jayapura"},
+		{"Bukan/Zona", "UTC"}, // tidak valid: kembali ke zona server
+	}
+
+	for _, tt := range tests {
+		t.Setenv("CLINIC_TIMEZONE", tt.env)
+		locationOnce = sync.Once{}
+
+		if got := Location().String(); got != tt.want {
+			t.Errorf("CLINIC_TIMEZONE=%q: Location() = %s, want %s", tt.env, got, tt.want)
+		}
+		if got := Now().Location(); got != Location() {
+			t.Errorf("CLINIC_TIMEZONE=%q: Now() in %s, want %s", tt.env, got, Location())
+		}
+	}
+	locationOnce = sync.Once{}
+}
+
+func TestNowIsClinicDate(t *testing.T) {
+	t.Setenv("CLINIC_TIMEZONE", "Asia/Jakarta")
+	locationOnce = sync.Once{}
+	defer func() { locationOnce = sync.Once{} }()
+
+	now := Now()
+	if _, offset := now.Zone(); offset != 7*3600 {
+		t.Errorf("Now() offset = %d, want %d", offset, 7*3600)
+	}
+	if want := time.Now().UTC().Add(7 * time.Hour).Format("2006-01-02"); now.Format("2006-01-02") != want {
+		t.Errorf("Now() date = %s, want %s", now.Format("2006-01-02"), want)
+	}
+}
+
+func TestSessionTimeZone(t *testing.T) {
+	load := func(name string) *time.Location {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return loc
+	}
+
+	tests := []struct {
+		loc  *time.Location
+		at   time.Time
+		want string
+	}{
+		{load("Asia/Jakarta"), time.Date(2026, 10, 18, 17, 30, 0, 0, time.UTC), "'+07:00'"},
+		{load("Asia/Makassar"), time.Date(2026, 10, 18, 17, 30, 0, 0, time.UTC), "'+08:00'"},
+		{load("Asia/Jayapura"), time.Date(2026, 10, 18, 17, 30, 0, 0, time.UTC), "'+09:00'"},
+		{load("Asia/Kolkata"), time.Date(2026, 10, 18, 17, 30, 0, 0, time.UTC), "'+05:30'"},
+		{time.UTC, time.Date(2026, 10, 18, 17, 30, 0, 0, time.UTC), "'+00:00'"},
+		{load("America/St_Johns"), time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC), "'-03:30'"},
+		{load("America/St_Johns"), time.Date(2026, 7, 15, 12, 0, 0, 0, time.UTC), "'-02:30'"},
+	}
+
+	for _, tt := range tests {
+		if got := sessionTimeZone(tt.at.In(tt.loc)); got != tt.want {
+			t.Errorf("sessionTimeZone(%s at %s) = %s, want %s", tt.loc, tt.at, got, tt.want)
+		}
+	}
+}
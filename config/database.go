@@ -2,10 +2,12 @@ package config
 
 import (
 	"database/sql"
+	"fmt"
 	"log"
 	"net/url"
 	"os"
 	"strings"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 )
@@ -28,7 +30,12 @@ func InitDB() {
 	host := u.Host
 	dbName := strings.TrimPrefix(u.Path, "/")
 
-	dsn := user + ":" + pass + "@tcp(" + host + ")/" + dbName + "?parseTime=true"
+	// Sesi MySQL memakai zona waktu klinik: NOW() & kolom DATETIME dibaca/ditulis dalam waktu klinik,
+	// bukan zona server DB. Offset dihitung saat start (untuk zona ber-DST, restart setelah pergantian).
+	tz := sessionTimeZone(Now())
+
+	dsn := user + ":" + pass + "@tcp(" + host + ")/" + dbName + "?parseTime=true" +
+		"&loc=" + url.QueryEscape(Location().String()) + "&time_zone=" + url.QueryEscape(tz)
 
 	log.Println("Connecting to DB host:", host)
 
@@ -43,3 +50,13 @@ func InitDB() {
 
 	log.Println("✓ Database connected successfully")
 }
+
+// sessionTimeZone - Nilai time_zone sesi MySQL ('+07:00') dari offset zona waktu t
+func sessionTimeZone(t time.Time) string {
+	_, offset := t.Zone()
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	return fmt.Sprintf("'%c%02d:%02d'", sign, offset/3600, offset%3600/60)
+}
@@ -0,0 +1,357 @@
+package config
+
+import (
+	"log"
+	"strings"
+)
+
+// column - Definisi kolom tambahan untuk tabel yang sudah ada
+type column struct {
+	Table      string
+	Name       string
+	Definition string
+}
+
+// tables - Tabel baru yang dibuat jika belum ada
+var tables = []string{
+	`CREATE TABLE IF NOT EXISTS consultation_versions (
+		version_id INT AUTO_INCREMENT PRIMARY KEY,
+		appointment_id INT NOT NULL,
+		versi INT NOT NULL,
+		gejala TEXT NULL,
+		diagnosa TEXT NULL,
+		resep_obat TEXT NULL,
+		tekanan_darah VARCHAR(20) NULL,
+		suhu DECIMAL(4,1) NULL,
+		berat_badan DECIMAL(5,1) NULL,
+		nadi INT NULL,
+		alasan TEXT NULL,
+		created_by INT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE KEY uq_consultation_versi (appointment_id, versi)
+	)`,
+	`CREATE TABLE IF NOT EXISTS lab_orders (
+		order_id INT AUTO_INCREMENT PRIMARY KEY,
+		appointment_id INT NOT NULL,
+		patient_id INT NOT NULL,
+		doctor_id INT NOT NULL,
+		jenis VARCHAR(20) NOT NULL,
+		pemeriksaan VARCHAR(150) NOT NULL,
+		catatan TEXT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'ordered',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		completed_at DATETIME NULL,
+		completed_by INT NULL,
+		INDEX idx_lab_orders_patient (patient_id),
+		INDEX idx_lab_orders_status (status)
+	)`,
+	`CREATE TABLE IF NOT EXISTS lab_results (
+		result_id INT AUTO_INCREMENT PRIMARY KEY,
+		order_id INT NOT NULL,
+		parameter VARCHAR(100) NOT NULL,
+		nilai DECIMAL(12,3) NULL,
+		satuan VARCHAR(30) NULL,
+		rujukan_min DECIMAL(12,3) NULL,
+		rujukan_max DECIMAL(12,3) NULL,
+		file_path VARCHAR(255) NULL,
+		file_name VARCHAR(255) NULL,
+		mime_type VARCHAR(100) NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		INDEX idx_lab_results_order (order_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS attachments (
+		attachment_id INT AUTO_INCREMENT PRIMARY KEY,
+		appointment_id INT NOT NULL,
+		uploaded_by INT NOT NULL,
+		file_key VARCHAR(255) NOT NULL,
+		file_name VARCHAR(255) NOT NULL,
+		mime_type VARCHAR(100) NOT NULL,
+		size BIGINT NOT NULL,
+		keterangan VARCHAR(255) NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		INDEX idx_attachments_appointment (appointment_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS documents (
+		document_id INT AUTO_INCREMENT PRIMARY KEY,
+		appointment_id INT NOT NULL,
+		jenis VARCHAR(20) NOT NULL,
+		kode_verifikasi VARCHAR(20) NOT NULL,
+		hari_istirahat INT NULL,
+		tanggal_mulai DATE NULL,
+		created_by INT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE KEY uq_documents_jenis (appointment_id, jenis),
+		UNIQUE KEY uq_documents_kode (kode_verifikasi)
+	)`,
+	`CREATE TABLE IF NOT EXISTS appointment_reminders (
+		appointment_id INT NOT NULL,
+		jenis VARCHAR(20) NOT NULL,
+		channel VARCHAR(20) NOT NULL,
+		sent_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (appointment_id, jenis, channel)
+	)`,
+	`CREATE TABLE IF NOT EXISTS notification_preferences (
+		user_id INT NOT NULL,
+		channel VARCHAR(20) NOT NULL,
+		aktif TINYINT(1) NOT NULL DEFAULT 1,
+		PRIMARY KEY (user_id, channel)
+	)`,
+	`CREATE TABLE IF NOT EXISTS notifications (
+		notification_id INT AUTO_INCREMENT PRIMARY KEY,
+		user_id INT NOT NULL,
+		judul VARCHAR(150) NOT NULL,
+		pesan TEXT NOT NULL,
+		link VARCHAR(255) NOT NULL DEFAULT '',
+		dibaca TINYINT(1) NOT NULL DEFAULT 0,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		INDEX idx_notifications_user (user_id, dibaca)
+	)`,
+	`CREATE TABLE IF NOT EXISTS webhooks (
+		webhook_id INT AUTO_INCREMENT PRIMARY KEY,
+		url VARCHAR(500) NOT NULL,
+		secret VARCHAR(100) NOT NULL,
+		events VARCHAR(255) NOT NULL DEFAULT '*',
+		aktif TINYINT(1) NOT NULL DEFAULT 1,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`,
+	`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		delivery_id INT AUTO_INCREMENT PRIMARY KEY,
+		webhook_id INT NOT NULL,
+		event_type VARCHAR(50) NOT NULL,
+		payload TEXT NOT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'pending',
+		attempts INT NOT NULL DEFAULT 0,
+		response_code INT NULL,
+		last_error VARCHAR(500) NULL,
+		next_attempt_at DATETIME NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		delivered_at DATETIME NULL,
+		INDEX idx_webhook_deliveries_due (status, next_attempt_at),
+		INDEX idx_webhook_deliveries_webhook (webhook_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS waitlist (
+		waitlist_id INT AUTO_INCREMENT PRIMARY KEY,
+		patient_id INT NOT NULL,
+		tanggal DATE NOT NULL,
+		doctor_id INT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'waiting',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		INDEX idx_waitlist_tanggal (tanggal, status)
+	)`,
+	`CREATE TABLE IF NOT EXISTS waitlist_offers (
+		offer_id INT AUTO_INCREMENT PRIMARY KEY,
+		waitlist_id INT NOT NULL,
+		source_appointment_id INT NOT NULL,
+		doctor_id INT NOT NULL,
+		tanggal DATE NOT NULL,
+		waktu VARCHAR(10) NOT NULL,
+		token VARCHAR(64) NOT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'offered',
+		expires_at DATETIME NOT NULL,
+		appointment_id INT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE KEY uq_waitlist_offers_token (token),
+		UNIQUE KEY uq_waitlist_offers_slot (source_appointment_id, waitlist_id),
+		INDEX idx_waitlist_offers_status (status, expires_at)
+	)`,
+	`CREATE TABLE IF NOT EXISTS holidays (
+		tanggal DATE PRIMARY KEY,
+		keterangan VARCHAR(150) NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS polyclinics (
+		poli_id INT AUTO_INCREMENT PRIMARY KEY,
+		kode VARCHAR(20) NOT NULL,
+		nama VARCHAR(100) NOT NULL,
+		aktif TINYINT(1) NOT NULL DEFAULT 1,
+		UNIQUE KEY uq_polyclinics_kode (kode)
+	)`,
+	`CREATE TABLE IF NOT EXISTS doctor_schedules (
+		doctor_id INT NOT NULL,
+		hari TINYINT NOT NULL,
+		jam_mulai TIME NOT NULL,
+		jam_selesai TIME NOT NULL,
+		PRIMARY KEY (doctor_id, hari)
+	)`,
+	`CREATE TABLE IF NOT EXISTS registration_sequences (
+		tanggal DATE PRIMARY KEY,
+		nomor_terakhir INT NOT NULL
+	)`,
+	// Satu baris per tanggal, dikunci saat booking supaya pengecekan kuota harian dan insert tidak balapan
+	`CREATE TABLE IF NOT EXISTS booking_locks (
+		tanggal DATE PRIMARY KEY
+	)`,
+}
+
+// seeds - Data awal; INSERT IGNORE sehingga aman dijalankan berulang dan tidak menimpa perubahan admin
+var seeds = []string{
+	`INSERT IGNORE INTO polyclinics (kode, nama) VALUES
+		('umum', 'Poli Umum'),
+		('gigi', 'Poli Gigi'),
+		('anak', 'Poli Anak'),
+		('kia', 'Poli KIA')`,
+}
+
+// columns - Kolom baru pada tabel lama (MySQL belum mendukung ADD COLUMN IF NOT EXISTS)
+var columns = []column{
+	// Tanda vital pasien saat konsultasi
+	{"appointments", "tekanan_darah", "VARCHAR(20) NULL"},
+	{"appointments", "suhu", "DECIMAL(4,1) NULL"},
+	{"appointments", "berat_badan", "DECIMAL(5,1) NULL"},
+	{"appointments", "nadi", "INT NULL"},
+
+	// Berapa kali pasien sudah mengubah jadwal sendiri
+	{"appointments", "reschedule_count", "INT NOT NULL DEFAULT 0"},
+
+	// Poli tujuan yang dipilih pasien saat booking
+	{"appointments", "poli_id", "INT NULL"},
+
+	// Nomor antrian harian per poli (pasien yang datang langsung)
+	{"appointments", "nomor_antrian", "INT NULL"},
+
+	// Dokter & jam dipilih otomatis (bukan oleh admin)
+	{"appointments", "auto_assigned", "TINYINT(1) NOT NULL DEFAULT 0"},
+	// Kapan dokter terakhir ditetapkan (urutan round robin auto-assign)
+	{"appointments", "assigned_at", "DATETIME NULL"},
+
+	// Pembatalan: alasan (dari daftar CANCEL_REASONS atau "Lainnya"), keterangan bebas, siapa & kapan
+	{"appointments", "alasan_batal", "VARCHAR(255) NULL"},
+	{"appointments", "keterangan_batal", "VARCHAR(255) NULL"},
+	{"appointments", "cancelled_by", "INT NULL"},
+	{"appointments", "cancelled_at", "DATETIME NULL"},
+
+	// Check-in di loket: kapan pasien datang & petugas yang mencatat
+	{"appointments", "arrived_at", "DATETIME NULL"},
+	{"appointments", "checked_in_by", "INT NULL"},
+
+	// Kontak pasien untuk notifikasi
+	{"users", "email", "VARCHAR(100) NULL"},
+	{"users", "no_hp", "VARCHAR(20) NULL"},
+
+	// Token rahasia untuk feed kalender .ics
+	{"users", "calendar_token", "VARCHAR(64) NULL UNIQUE"},
+
+	// Poli & spesialisasi dokter
+	{"users", "poli_id", "INT NULL"},
+	{"users", "spesialisasi", "VARCHAR(100) NULL"},
+
+	// Kode sekali pakai untuk mengaktifkan akun pasien yang didaftarkan di loket
+	{"users", "kode_aktivasi", "VARCHAR(20) NULL"},
+}
+
+// indexes - Index tambahan pada tabel lama (nama index, definisi kolom); nama berawalan "uq_" dibuat UNIQUE
+var indexes = []column{
+	// Filter & keyset pagination daftar appointment admin
+	{"appointments", "idx_appointments_tanggal", "(tanggal_konsultasi, appointment_id)"},
+	{"appointments", "idx_appointments_created", "(created_at, appointment_id)"},
+	{"appointments", "idx_appointments_status", "(status, tanggal_konsultasi)"},
+	{"appointments", "idx_appointments_doctor", "(doctor_id, tanggal_konsultasi)"},
+
+	// Nomor registrasi dipakai untuk pencarian & check-in, tidak boleh kembar
+	{"appointments", "uq_appointments_nomor_registrasi", "(nomor_registrasi)"},
+}
+
+// indexCleanups - Perbaikan data lama yang dijalankan sebelum unique index dibuat
+var indexCleanups = map[string]string{
+	// Nomor lama REG-<user>-<detik> bisa kembar jika dua booking terjadi di detik yang sama;
+	// appointment pertama memakai nomor aslinya, sisanya diberi akhiran ID appointment
+	"uq_appointments_nomor_registrasi": `
+		UPDATE appointments a
+		JOIN (SELECT nomor_registrasi, MIN(appointment_id) AS pertama FROM appointments
+		      GROUP BY nomor_registrasi HAVING COUNT(*) > 1) d
+		  ON a.nomor_registrasi = d.nomor_registrasi AND a.appointment_id <> d.pertama
+		SET a.nomor_registrasi = CONCAT(a.nomor_registrasi, '-', a.appointment_id)`,
+}
+
+// enumColumns - Kolom ENUM diubah ke VARCHAR supaya nilai baru (status/role) bisa dipakai
+var enumColumns = []column{
+	{"appointments", "status", "VARCHAR(20) NOT NULL DEFAULT 'pending'"},
+	{"users", "role", "VARCHAR(20) NOT NULL DEFAULT 'pasien'"},
+}
+
+// Migrate - Menyesuaikan skema database dengan kebutuhan aplikasi
+func Migrate() {
+	for _, stmt := range tables {
+		if _, err := DB.Exec(stmt); err != nil {
+			log.Fatal("Error creating table:", err)
+		}
+	}
+
+	for _, c := range columns {
+		var count int
+		err := DB.QueryRow(`
+			SELECT COUNT(*) FROM information_schema.COLUMNS
+			WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?
+		`, c.Table, c.Name).Scan(&count)
+		if err != nil {
+			log.Fatal("Error checking column:", err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		if _, err := DB.Exec("ALTER TABLE " + c.Table + " ADD COLUMN " + c.Name + " " + c.Definition); err != nil {
+			log.Fatal("Error adding column "+c.Table+"."+c.Name+":", err)
+		}
+		log.Printf("✓ Column added: %s.%s", c.Table, c.Name)
+	}
+
+	for _, idx := range indexes {
+		var count int
+		err := DB.QueryRow(`
+			SELECT COUNT(*) FROM information_schema.STATISTICS
+			WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND INDEX_NAME = ?
+		`, idx.Table, idx.Name).Scan(&count)
+		if err != nil {
+			log.Fatal("Error checking index:", err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		if cleanup, ok := indexCleanups[idx.Name]; ok {
+			res, err := DB.Exec(cleanup)
+			if err != nil {
+				log.Fatal("Error cleaning up data for index "+idx.Name+":", err)
+			}
+			if n, _ := res.RowsAffected(); n > 0 {
+				log.Printf("✓ %d duplicate row(s) fixed before creating %s.%s", n, idx.Table, idx.Name)
+			}
+		}
+
+		stmt := "CREATE INDEX "
+		if strings.HasPrefix(idx.Name, "uq_") {
+			stmt = "CREATE UNIQUE INDEX "
+		}
+		if _, err := DB.Exec(stmt + idx.Name + " ON " + idx.Table + " " + idx.Definition); err != nil {
+			log.Fatal("Error creating index "+idx.Name+":", err)
+		}
+		log.Printf("✓ Index created: %s.%s", idx.Table, idx.Name)
+	}
+
+	for _, c := range enumColumns {
+		var dataType string
+		err := DB.QueryRow(`
+			SELECT DATA_TYPE FROM information_schema.COLUMNS
+			WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?
+		`, c.Table, c.Name).Scan(&dataType)
+		if err != nil {
+			log.Fatal("Error checking column:", err)
+		}
+		if dataType != "enum" {
+			continue
+		}
+
+		if _, err := DB.Exec("ALTER TABLE " + c.Table + " MODIFY COLUMN " + c.Name + " " + c.Definition); err != nil {
+			log.Fatal("Error modifying column "+c.Table+"."+c.Name+":", err)
+		}
+		log.Printf("✓ Column modified: %s.%s", c.Table, c.Name)
+	}
+
+	for _, stmt := range seeds {
+		if _, err := DB.Exec(stmt); err != nil {
+			log.Fatal("Error seeding data:", err)
+		}
+	}
+
+	log.Println("✓ Database schema up to date")
+}
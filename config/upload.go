@@ -0,0 +1,29 @@
+package config
+
+import (
+	"klinik-app/storage"
+	"log"
+	"os"
+)
+
+// MaxUploadSize - Batas ukuran file upload (10 MB)
+const MaxUploadSize = 10 << 20
+
+// Files - Backend penyimpanan file upload
+var Files storage.Storage
+
+// UploadDir - Folder penyimpanan file upload di disk lokal
+func UploadDir() string {
+	return getEnv("UPLOAD_DIR", "uploads")
+}
+
+// InitStorage - Siapkan backend penyimpanan file
+func InitStorage() {
+	dir := UploadDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		log.Fatal("Error creating upload dir:", err)
+	}
+
+	Files = storage.NewLocal(dir)
+	log.Println("✓ File storage ready:", dir)
+}
@@ -0,0 +1,105 @@
+// Package events - Event bus in-process untuk perubahan status appointment
+package events
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Jenis event siklus hidup appointment
+const (
+	AppointmentCreated     = "appointment.created"
+	AppointmentApproved    = "appointment.approved"
+	AppointmentRescheduled = "appointment.rescheduled"
+	AppointmentCancelled   = "appointment.cancelled"
+	AppointmentCompleted   = "appointment.completed"
+	AppointmentNoShow      = "appointment.no_show"
+	AppointmentCheckedIn   = "appointment.checked_in"
+)
+
+// All - Semua jenis event (untuk pilihan langganan webhook)
+var All = []string{
+	AppointmentCreated,
+	AppointmentApproved,
+	AppointmentRescheduled,
+	AppointmentCancelled,
+	AppointmentCompleted,
+	AppointmentNoShow,
+	AppointmentCheckedIn,
+}
+
+type Event struct {
+	Type          string    `json:"type"`
+	AppointmentID int       `json:"appointment_id"`
+	At            time.Time `json:"at"`
+}
+
+// Handler - Dipanggil di goroutine terpisah untuk setiap event
+type Handler func(ctx context.Context, e Event)
+
+var (
+	mu        sync.RWMutex
+	handlers  []Handler
+	listeners = map[chan Event]struct{}{}
+)
+
+// Subscribe - Daftarkan handler untuk semua event
+func Subscribe(h Handler) {
+	mu.Lock()
+	defer mu.Unlock()
+	handlers = append(handlers, h)
+}
+
+// Listen - Berlangganan event lewat channel (untuk koneksi streaming seperti SSE).
+// Panggil fungsi stop saat selesai; event dibuang jika channel penuh supaya Publish tidak pernah tertahan.
+func Listen(buffer int) (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+
+	mu.Lock()
+	listeners[ch] = struct{}{}
+	mu.Unlock()
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			mu.Lock()
+			delete(listeners, ch)
+			mu.Unlock()
+			close(ch)
+		})
+	}
+
+	return ch, stop
+}
+
+// Publish - Kirim event ke semua handler tanpa menunggu (error/panic handler tidak mengganggu pemanggil)
+func Publish(eventType string, appointmentID int) {
+	e := Event{Type: eventType, AppointmentID: appointmentID, At: time.Now()}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, h := range handlers {
+		go func(h Handler) {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("❌ Event handler panic on %s: %v", e.Type, r)
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+			h(ctx, e)
+		}(h)
+	}
+
+	for ch := range listeners {
+		select {
+		case ch <- e:
+		default:
+			log.Printf("⚠️ Event %s dibuang: listener lambat", e.Type)
+		}
+	}
+}
@@ -2,12 +2,19 @@ package handlers
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	"html/template"
+	"klinik-app/assign"
+	"klinik-app/booking"
 	"klinik-app/config"
 	"klinik-app/middleware"
 	"klinik-app/models"
+	"klinik-app/regno"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 )
@@ -15,17 +22,71 @@ import (
 // AdminDashboard - Dashboard untuk admin
 func AdminDashboard(w http.ResponseWriter, r *http.Request) {
 	sess := middleware.GetSession(r)
+	q := r.URL.Query()
+
+	doctorID, _ := strconv.Atoi(q.Get("dokter"))
+	filter := models.AppointmentFilter{
+		Status:   q.Get("status"),
+		Dari:     q.Get("dari"),
+		Sampai:   q.Get("sampai"),
+		DoctorID: doctorID,
+		Pasien:   strings.TrimSpace(q.Get("pasien")),
+		NomorReg: strings.TrimSpace(q.Get("noreg")),
+		Sort:     q.Get("urut"),
+		Cursor:   q.Get("cursor"),
+	}
+
+	// Nomor registrasi lengkap yang digit ceknya salah hampir pasti salah ketik
+	var noregWarning string
+	if filter.NomorReg != "" && regno.Verify(config.RegistrationFormat(), regno.Normalize(filter.NomorReg)) == regno.ErrCheckDigit {
+		noregWarning = regno.ErrCheckDigit.Error()
+	}
+
+	page, err := models.GetAppointmentsPage(config.DB, filter)
+	if err == models.ErrInvalidCursor {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	// ✅ Ubah dari GetPendingAppointments ke GetAllAppointments
-	appointments, err := models.GetAllAppointments(config.DB)
+	// Link halaman pertama/berikutnya memakai filter yang sama (parameter kosong dibuang)
+	q.Del("cursor")
+	for k, v := range q {
+		if len(v) == 0 || v[0] == "" {
+			q.Del(k)
+		}
+	}
+	firstURL := "/admin/dashboard?" + q.Encode()
+	var nextURL string
+	if page.Next != "" {
+		q.Set("cursor", page.Next)
+		nextURL = "/admin/dashboard?" + q.Encode()
+		q.Del("cursor")
+	}
+
+	// Pilihan dokter untuk approve massal
+	doctors, err := models.GetDoctors(config.DB)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	data := map[string]interface{}{
-		"Nama":         sess["Nama"],
-		"Appointments": appointments,
+		"Nama":          sess["Nama"],
+		"Unread":        unreadCount(sess),
+		"Appointments":  page.Appointments,
+		"Doctors":       doctors,
+		"CancelReasons": append(config.CancelReasons(), alasanLainnya),
+		"Filter":        filter,
+		"NomorRegWarn":  noregWarning,
+		"FirstURL":      firstURL,
+		"NextURL":       nextURL,
+		"FirstPage":     filter.Cursor == "",
+		"Filtered":      filtered(q),
+		"Statuses":      []string{"pending", "approved", "in_progress", "completed", "no_show", "cancelled"},
 	}
 
 	tmpl, err := template.ParseFiles("templates/admin_dashboard.html")
@@ -36,21 +97,105 @@ func AdminDashboard(w http.ResponseWriter, r *http.Request) {
 	tmpl.Execute(w, data)
 }
 
+// filtered - True jika ada filter aktif; urutan (urut) saja bukan filter
+func filtered(q url.Values) bool {
+	for k := range q {
+		if k != "urut" {
+			return true
+		}
+	}
+	return false
+}
+
+// doctorChoices - Dokter poli (0 = semua poli) yang praktik pada tanggal tersebut.
+// Jika tidak ada yang berjadwal, semua dokter dikembalikan (onSchedule = false).
+func doctorChoices(poliID int, tanggal string) (doctors []models.DoctorOnDuty, onSchedule bool, err error) {
+	doctors, err = models.GetDoctorsOnDuty(config.DB, poliID, tanggal)
+	if err != nil || len(doctors) > 0 {
+		return doctors, true, err
+	}
+
+	doctors, err = allDoctors()
+	return doctors, false, err
+}
+
+// allDoctors - Semua dokter tanpa memperhatikan jadwal praktik
+func allDoctors() ([]models.DoctorOnDuty, error) {
+	all, err := models.GetDoctors(config.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	var doctors []models.DoctorOnDuty
+	for _, d := range all {
+		doctors = append(doctors, models.DoctorOnDuty{User: d})
+	}
+	return doctors, nil
+}
+
+// approvalDoctors - Pilihan dokter untuk approval: dokter poli tujuan yang praktik pada tanggal appointment
+// (appointment lama tanpa poli: semua dokter)
+func approvalDoctors(apt *models.Appointment) ([]models.DoctorOnDuty, bool, error) {
+	if !apt.PoliID.Valid {
+		doctors, err := allDoctors()
+		return doctors, false, err
+	}
+	return doctorChoices(int(apt.PoliID.Int64), apt.TanggalKonsultasi.Format("2006-01-02"))
+}
+
 // AdminApprovePage - Form approve appointment
 func AdminApprovePage(w http.ResponseWriter, r *http.Request) {
+	renderApproveForm(w, r, "")
+}
+
+// renderApproveForm - Form approve beserta dokter yang bisa dipilih dan pesan error (jika ada)
+func renderApproveForm(w http.ResponseWriter, r *http.Request, errMsg string) {
 	vars := mux.Vars(r)
 	appointmentID := vars["id"]
 
-	// Get list dokter
-	doctors, err := models.GetDoctors(config.DB)
+	id, _ := strconv.Atoi(appointmentID)
+	apt, err := models.GetAppointmentByID(config.DB, id)
+	if err != nil {
+		http.Error(w, "Appointment tidak ditemukan", http.StatusNotFound)
+		return
+	}
+
+	doctors, onSchedule, err := approvalDoctors(apt)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	// Riwayat ketidakhadiran pasien sebagai bahan pertimbangan admin
+	policy := config.Booking()
+	noShows, err := models.CountNoShows(config.DB, apt.PatientID, policy.NoShowWindowDays, config.Now().Format("2006-01-02"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Saran dokter & jam menurut strategi auto-assign; admin bebas memilih yang lain
+	doctorID, waktu := r.FormValue("doctor_id"), r.FormValue("waktu")
+	suggestion, err := assign.Suggest(config.DB, policy, config.AutoAssignStrategy(), apt, config.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if suggestion != nil && doctorID == "" {
+		doctorID, waktu = strconv.Itoa(suggestion.DoctorID), suggestion.Waktu
+	}
+
 	data := map[string]interface{}{
 		"AppointmentID": appointmentID,
+		"Appointment":   apt,
 		"Doctors":       doctors,
+		"OnSchedule":    onSchedule,
+		"NoShows":       noShows,
+		"NoShowWindow":  policy.NoShowWindowDays,
+		"Error":         errMsg,
+		"Suggestion":    suggestion,
+		"DoctorID":      doctorID,
+		"Waktu":         waktu,
 	}
 
 	tmpl, err := template.ParseFiles("templates/admin_approve.html")
@@ -58,6 +203,9 @@ func AdminApprovePage(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if errMsg != "" {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
 	tmpl.Execute(w, data)
 }
 
@@ -73,8 +221,40 @@ func AdminApproveHandler(w http.ResponseWriter, r *http.Request) {
 	doctorID, _ := strconv.Atoi(r.FormValue("doctor_id"))
 	waktu := r.FormValue("waktu")
 
+	apt, err := models.GetAppointmentByID(config.DB, appointmentID)
+	if err != nil {
+		http.Error(w, "Appointment tidak ditemukan", http.StatusNotFound)
+		return
+	}
+
+	// Dokter harus salah satu pilihan di form, dan jam konsultasi di dalam jam praktiknya
+	doctors, _, err := approvalDoctors(apt)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var dokter *models.DoctorOnDuty
+	for i := range doctors {
+		if doctors[i].UserID == doctorID {
+			dokter = &doctors[i]
+		}
+	}
+	if dokter == nil {
+		renderApproveForm(w, r, "Dokter tidak tersedia untuk poli/tanggal appointment ini")
+		return
+	}
+	if dokter.JamMulai != "" && (waktu < dokter.JamMulai || waktu >= dokter.JamSelesai) {
+		renderApproveForm(w, r, fmt.Sprintf("%s praktik pukul %s–%s, silakan pilih jam di dalam jadwal tersebut",
+			dokter.Nama, dokter.JamMulai, dokter.JamSelesai))
+		return
+	}
+
 	// Update appointment
-	err := models.ApproveAppointment(config.DB, appointmentID, doctorID, waktu)
+	err = models.ApproveAppointment(config.DB, appointmentID, doctorID, apt.TanggalKonsultasi.Format("2006-01-02"), waktu)
+	if err == models.ErrSlotTaken || err == models.ErrNotPending {
+		renderApproveForm(w, r, err.Error())
+		return
+	}
 	if err != nil {
 		http.Error(w, "Gagal approve: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -170,7 +350,12 @@ func AdminRescheduleHandler(w http.ResponseWriter, r *http.Request) {
 	tanggal := r.FormValue("tanggal")
 	waktu := r.FormValue("waktu")
 
-	err := models.RescheduleAppointment(config.DB, appointmentID, doctorID, tanggal, waktu)
+	err := booking.AdminReschedule(config.DB, config.Booking(), appointmentID, doctorID, tanggal, waktu)
+	var ruleErr *booking.RuleError
+	if errors.As(err, &ruleErr) {
+		http.Error(w, ruleErr.Message, http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Gagal reschedule: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -186,9 +371,26 @@ func AdminCancelAppointment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	sess := middleware.GetSession(r)
 	appointmentID, _ := strconv.Atoi(r.FormValue("appointment_id"))
 
-	err := models.CancelAppointment(config.DB, appointmentID)
+	apt, err := models.GetAppointmentByID(config.DB, appointmentID)
+	if err != nil {
+		http.Error(w, "Appointment tidak ditemukan", http.StatusNotFound)
+		return
+	}
+
+	alasan, keterangan, errMsg := cancelReason(r)
+	if errMsg != "" {
+		renderCancelForm(w, apt, "/admin/cancel-appointment", "/admin/dashboard", errMsg)
+		return
+	}
+
+	err = models.CancelAppointment(config.DB, appointmentID, sess["UserID"].(int), alasan, keterangan)
+	if err == models.ErrNotCancellable {
+		renderCancelForm(w, apt, "/admin/cancel-appointment", "/admin/dashboard", "Appointment ini sudah tidak bisa dibatalkan")
+		return
+	}
 	if err != nil {
 		http.Error(w, "Gagal cancel: "+err.Error(), http.StatusInternalServerError)
 		return
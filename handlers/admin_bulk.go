@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+	"html/template"
+	"klinik-app/booking"
+	"klinik-app/config"
+	"klinik-app/middleware"
+	"klinik-app/models"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxBulk - Batas jumlah appointment dalam satu aksi massal
+const maxBulk = 200
+
+// AdminBulkHandler - Approve, cancel atau pindah tanggal banyak appointment sekaligus (satu transaksi)
+func AdminBulkHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Form tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	var ids []int
+	for _, v := range r.Form["ids"] {
+		if id, err := strconv.Atoi(v); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		http.Error(w, "Pilih minimal satu appointment", http.StatusBadRequest)
+		return
+	}
+	if len(ids) > maxBulk {
+		http.Error(w, "Maksimal "+strconv.Itoa(maxBulk)+" appointment per aksi", http.StatusBadRequest)
+		return
+	}
+
+	policy := config.Booking()
+	var (
+		results []models.BulkResult
+		aksi    string
+		err     error
+	)
+
+	switch r.FormValue("aksi") {
+	case "approve":
+		aksi = "Approve"
+		doctorID, _ := strconv.Atoi(r.FormValue("doctor_id"))
+		mulai := r.FormValue("waktu")
+		if _, perr := time.Parse("15:04", mulai); perr != nil || doctorID == 0 {
+			http.Error(w, "Dokter dan jam mulai wajib diisi untuk approve", http.StatusBadRequest)
+			return
+		}
+		if _, derr := models.GetDoctor(config.DB, doctorID); derr != nil {
+			http.Error(w, "Dokter tidak ditemukan", http.StatusBadRequest)
+			return
+		}
+		results, err = models.BulkApprove(config.DB, ids, doctorID, mulai, booking.Slots(policy), config.Now())
+
+	case "cancel":
+		aksi = "Cancel"
+		alasan, keterangan, errMsg := cancelReason(r)
+		if errMsg != "" {
+			http.Error(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		sess := middleware.GetSession(r)
+		results, err = models.BulkCancel(config.DB, ids, sess["UserID"].(int), alasan, keterangan)
+
+	case "reschedule":
+		aksi = "Pindah tanggal"
+		tanggal := r.FormValue("tanggal")
+		var ruleErr *booking.RuleError
+		if cerr := booking.CheckOpen(config.DB, policy, tanggal); errors.As(cerr, &ruleErr) {
+			http.Error(w, ruleErr.Message, http.StatusBadRequest)
+			return
+		} else if cerr != nil {
+			http.Error(w, cerr.Error(), http.StatusInternalServerError)
+			return
+		}
+		if tanggal < config.Now().Format("2006-01-02") {
+			http.Error(w, "Tanggal baru sudah lewat", http.StatusBadRequest)
+			return
+		}
+		results, err = booking.BulkReschedule(config.DB, policy, ids, tanggal)
+
+	default:
+		http.Error(w, "Aksi tidak dikenali", http.StatusBadRequest)
+		return
+	}
+
+	if err != nil {
+		http.Error(w, "Aksi massal dibatalkan, tidak ada perubahan disimpan: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	berhasil := 0
+	for _, res := range results {
+		if res.OK {
+			berhasil++
+		}
+	}
+
+	data := map[string]interface{}{
+		"Aksi":     aksi,
+		"Results":  results,
+		"Berhasil": berhasil,
+		"Dilewati": len(results) - berhasil,
+	}
+
+	tmpl, err := template.ParseFiles("templates/admin_bulk_hasil.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tmpl.Execute(w, data)
+}
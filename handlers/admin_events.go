@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"klinik-app/config"
+	"klinik-app/events"
+	"klinik-app/models"
+	"log"
+	"net/http"
+	"time"
+)
+
+// AdminEvents - Stream Server-Sent Events berisi baris appointment yang baru dibuat/berubah
+func AdminEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming tidak didukung", http.StatusInternalServerError)
+		return
+	}
+
+	// Baris dirender dengan template yang sama dengan dashboard supaya tampilannya identik
+	tmpl, err := template.ParseFiles("templates/admin_dashboard.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ch, stop := events.Listen(32)
+	defer stop()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	fmt.Fprint(w, "retry: 5000\n\n")
+	flusher.Flush()
+
+	ping := time.NewTicker(30 * time.Second)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case <-ping.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+
+		case e := <-ch:
+			apt, err := models.GetAppointmentByID(config.DB, e.AppointmentID)
+			if err != nil {
+				log.Printf("❌ SSE %s: appointment %d: %v", e.Type, e.AppointmentID, err)
+				continue
+			}
+
+			var row bytes.Buffer
+			if err := tmpl.ExecuteTemplate(&row, "appointment-row", apt); err != nil {
+				log.Printf("❌ SSE render appointment %d: %v", e.AppointmentID, err)
+				continue
+			}
+
+			payload, _ := json.Marshal(map[string]interface{}{
+				"type":           e.Type,
+				"appointment_id": e.AppointmentID,
+				"html":           row.String(),
+			})
+			fmt.Fprintf(w, "event: appointment\nid: %d\ndata: %s\n\n", e.At.UnixNano(), payload)
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"html/template"
+	"klinik-app/config"
+	"klinik-app/models"
+	"net/http"
+	"time"
+)
+
+// AdminLaporanPage - Laporan pembatalan per alasan (default bulan berjalan)
+func AdminLaporanPage(w http.ResponseWriter, r *http.Request) {
+	now := config.Now()
+	from := r.URL.Query().Get("dari")
+	to := r.URL.Query().Get("sampai")
+	if _, err := time.Parse("2006-01-02", from); err != nil {
+		from = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).Format("2006-01-02")
+	}
+	if _, err := time.Parse("2006-01-02", to); err != nil {
+		to = now.Format("2006-01-02")
+	}
+
+	stats, err := models.GetCancellationStats(config.DB, from, to)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var total models.CancellationStat
+	for _, s := range stats {
+		total.OlehPasien += s.OlehPasien
+		total.OlehKlinik += s.OlehKlinik
+		total.Total += s.Total
+	}
+
+	data := map[string]interface{}{
+		"Dari":   from,
+		"Sampai": to,
+		"Stats":  stats,
+		"Total":  total,
+	}
+
+	tmpl, err := template.ParseFiles("templates/admin_laporan.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tmpl.Execute(w, data)
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"html/template"
+	"klinik-app/booking"
+	"klinik-app/config"
+	"klinik-app/models"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// AdminLiburPage - Kalender hari libur klinik
+func AdminLiburPage(w http.ResponseWriter, r *http.Request) {
+	holidays, err := models.GetUpcomingHolidays(config.DB, config.Now().Format("2006-01-02"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]interface{}{
+		"Holidays": holidays,
+		"Imported": r.URL.Query().Get("imported"),
+		"Error":    r.URL.Query().Get("error"),
+	}
+
+	tmpl, err := template.ParseFiles("templates/admin_libur.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tmpl.Execute(w, data)
+}
+
+// AdminLiburHandler - Tambah satu hari libur
+func AdminLiburHandler(w http.ResponseWriter, r *http.Request) {
+	tanggal := r.FormValue("tanggal")
+	keterangan := strings.TrimSpace(r.FormValue("keterangan"))
+
+	if _, err := time.Parse("2006-01-02", tanggal); err != nil || keterangan == "" {
+		http.Error(w, "Tanggal dan keterangan wajib diisi", http.StatusBadRequest)
+		return
+	}
+
+	if err := models.SaveHoliday(config.DB, tanggal, keterangan); err != nil {
+		http.Error(w, "Gagal simpan hari libur: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/libur", http.StatusSeeOther)
+}
+
+// AdminLiburImportHandler - Import hari libur nasional dari file CSV
+func AdminLiburImportHandler(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "File CSV wajib dipilih (maksimal 1 MB)", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	n, err := booking.ImportHolidays(config.DB, file)
+	if err != nil {
+		http.Redirect(w, r, "/admin/libur?error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/libur?imported="+strconv.Itoa(n), http.StatusSeeOther)
+}
+
+// AdminLiburDeleteHandler - Hapus hari libur
+func AdminLiburDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	if err := models.DeleteHoliday(config.DB, mux.Vars(r)["tanggal"]); err != nil {
+		http.Error(w, "Gagal hapus hari libur: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/libur", http.StatusSeeOther)
+}
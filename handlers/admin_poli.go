@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"html/template"
+	"klinik-app/booking"
+	"klinik-app/config"
+	"klinik-app/models"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+var kodePoliPattern = regexp.MustCompile(`^[a-z0-9_-]{1,20}$`)
+
+// AdminPoliPage - Daftar poli dan dokter beserta poli/spesialisasinya
+func AdminPoliPage(w http.ResponseWriter, r *http.Request) {
+	polis, err := models.GetPolis(config.DB)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	doctors, err := models.GetDoctors(config.DB)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]interface{}{
+		"Polis":   polis,
+		"Doctors": doctors,
+	}
+
+	tmpl, err := template.ParseFiles("templates/admin_poli.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tmpl.Execute(w, data)
+}
+
+// AdminPoliHandler - Tambah poli baru
+func AdminPoliHandler(w http.ResponseWriter, r *http.Request) {
+	kode := strings.ToLower(strings.TrimSpace(r.FormValue("kode")))
+	nama := strings.TrimSpace(r.FormValue("nama"))
+
+	if !kodePoliPattern.MatchString(kode) || nama == "" {
+		http.Error(w, "Kode (huruf kecil/angka, maks. 20 karakter) dan nama poli wajib diisi", http.StatusBadRequest)
+		return
+	}
+
+	if err := models.CreatePoli(config.DB, kode, nama); err != nil {
+		http.Error(w, "Gagal simpan poli: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/poli", http.StatusSeeOther)
+}
+
+// AdminPoliToggleHandler - Aktifkan/nonaktifkan poli
+func AdminPoliToggleHandler(w http.ResponseWriter, r *http.Request) {
+	poliID, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	if err := models.SetPoliActive(config.DB, poliID, r.FormValue("aktif") == "1"); err != nil {
+		http.Error(w, "Gagal ubah status poli: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/poli", http.StatusSeeOther)
+}
+
+// jadwalHari - Baris form jadwal praktik untuk satu hari
+type jadwalHari struct {
+	Hari       int
+	Nama       string
+	Aktif      bool
+	JamMulai   string
+	JamSelesai string
+}
+
+// AdminDokterPage - Form poli, spesialisasi dan jadwal praktik mingguan dokter
+func AdminDokterPage(w http.ResponseWriter, r *http.Request) {
+	doctorID, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	dokter, err := models.GetDoctor(config.DB, doctorID)
+	if err != nil {
+		http.Error(w, "Dokter tidak ditemukan", http.StatusNotFound)
+		return
+	}
+
+	polis, err := models.GetPolis(config.DB)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	schedules, err := models.GetDoctorSchedules(config.DB, doctorID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Senin dulu, Minggu terakhir; hari tanpa jadwal diisi jam buka/tutup klinik
+	policy := config.Booking()
+	var jadwal []jadwalHari
+	for i := 1; i <= 7; i++ {
+		day := time.Weekday(i % 7)
+		j := jadwalHari{
+			Hari:       int(day),
+			Nama:       booking.NamaHari(day),
+			JamMulai:   time.Time{}.Add(policy.OpenTime).Format("15:04"),
+			JamSelesai: time.Time{}.Add(policy.CloseTime).Format("15:04"),
+		}
+		for _, s := range schedules {
+			if s.Hari == int(day) {
+				j.Aktif, j.JamMulai, j.JamSelesai = true, s.JamMulai, s.JamSelesai
+			}
+		}
+		jadwal = append(jadwal, j)
+	}
+
+	var poliID int64
+	if dokter.PoliID.Valid {
+		poliID = dokter.PoliID.Int64
+	}
+
+	data := map[string]interface{}{
+		"Dokter": dokter,
+		"PoliID": int(poliID),
+		"Polis":  polis,
+		"Jadwal": jadwal,
+		"Saved":  r.URL.Query().Get("saved") == "1",
+	}
+
+	tmpl, err := template.ParseFiles("templates/admin_dokter.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tmpl.Execute(w, data)
+}
+
+// AdminDokterHandler - Simpan poli, spesialisasi dan jadwal praktik dokter
+func AdminDokterHandler(w http.ResponseWriter, r *http.Request) {
+	doctorID, _ := strconv.Atoi(mux.Vars(r)["id"])
+	poliID, _ := strconv.Atoi(r.FormValue("poli_id"))
+	spesialisasi := strings.TrimSpace(r.FormValue("spesialisasi"))
+
+	if _, err := models.GetDoctor(config.DB, doctorID); err != nil {
+		http.Error(w, "Dokter tidak ditemukan", http.StatusNotFound)
+		return
+	}
+
+	var schedules []models.DoctorSchedule
+	for day := 0; day < 7; day++ {
+		prefix := "hari_" + strconv.Itoa(day)
+		if r.FormValue(prefix) != "on" {
+			continue
+		}
+
+		mulai, selesai := r.FormValue(prefix+"_mulai"), r.FormValue(prefix+"_selesai")
+		_, errMulai := time.Parse("15:04", mulai)
+		_, errSelesai := time.Parse("15:04", selesai)
+		if errMulai != nil || errSelesai != nil || mulai >= selesai {
+			http.Error(w, "Jam praktik hari "+booking.NamaHari(time.Weekday(day))+" tidak valid", http.StatusBadRequest)
+			return
+		}
+		schedules = append(schedules, models.DoctorSchedule{DoctorID: doctorID, Hari: day, JamMulai: mulai, JamSelesai: selesai})
+	}
+
+	if err := models.UpdateDoctorProfile(config.DB, doctorID, poliID, spesialisasi); err != nil {
+		http.Error(w, "Gagal simpan profil dokter: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := models.SaveDoctorSchedules(config.DB, doctorID, schedules); err != nil {
+		http.Error(w, "Gagal simpan jadwal: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/dokter/"+strconv.Itoa(doctorID)+"?saved=1", http.StatusSeeOther)
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"html/template"
+	"klinik-app/config"
+	"klinik-app/models"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// AdminWalkInPage - Loket: cari pasien (NIK/nama) atau daftarkan pasien baru
+func AdminWalkInPage(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+
+	var patients []models.User
+	if q != "" {
+		var err error
+		patients, err = models.SearchPatients(config.DB, q, 20)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	data := map[string]interface{}{
+		"Q":        q,
+		"Patients": patients,
+	}
+
+	tmpl, err := template.ParseFiles("templates/admin_walkin.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tmpl.Execute(w, data)
+}
+
+// AdminWalkInPasienHandler - Daftarkan pasien baru di loket (tanpa password)
+func AdminWalkInPasienHandler(w http.ResponseWriter, r *http.Request) {
+	nik := strings.TrimSpace(r.FormValue("nik"))
+	nama := strings.TrimSpace(r.FormValue("nama"))
+	email := strings.TrimSpace(r.FormValue("email"))
+	noHP := strings.TrimSpace(r.FormValue("no_hp"))
+
+	if len(nik) != 16 || strings.Trim(nik, "0123456789") != "" || nama == "" {
+		http.Error(w, "NIK (16 digit) dan nama wajib diisi", http.StatusBadRequest)
+		return
+	}
+
+	if existing, _ := models.GetUserByNIK(config.DB, nik); existing != nil {
+		http.Error(w, "NIK sudah terdaftar, silakan cari pasien tersebut", http.StatusConflict)
+		return
+	}
+
+	patientID, err := models.CreateWalkInPatient(config.DB, nik, nama, email, noHP)
+	if err != nil {
+		http.Error(w, "Gagal daftar pasien: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/walkin/"+strconv.Itoa(patientID), http.StatusSeeOther)
+}
+
+// AdminWalkInDaftarPage - Pilih dokter yang praktik hari ini untuk pasien yang datang langsung
+func AdminWalkInDaftarPage(w http.ResponseWriter, r *http.Request) {
+	renderWalkInForm(w, r, "")
+}
+
+// renderWalkInForm - Form pendaftaran walk-in beserta pesan error (jika ada)
+func renderWalkInForm(w http.ResponseWriter, r *http.Request, errMsg string) {
+	patientID, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	patient, err := models.GetUserByID(config.DB, patientID)
+	if err != nil || patient.Role != "pasien" {
+		http.Error(w, "Pasien tidak ditemukan", http.StatusNotFound)
+		return
+	}
+
+	doctors, onSchedule, err := doctorChoices(0, config.Now().Format("2006-01-02"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]interface{}{
+		"Patient":    patient,
+		"Doctors":    doctors,
+		"OnSchedule": onSchedule,
+		"Error":      errMsg,
+	}
+
+	tmpl, err := template.ParseFiles("templates/admin_walkin_daftar.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if errMsg != "" {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	tmpl.Execute(w, data)
+}
+
+// AdminWalkInDaftarHandler - Buat appointment hari ini (langsung approved) dengan nomor antrian
+func AdminWalkInDaftarHandler(w http.ResponseWriter, r *http.Request) {
+	patientID, _ := strconv.Atoi(mux.Vars(r)["id"])
+	doctorID, _ := strconv.Atoi(r.FormValue("doctor_id"))
+
+	patient, err := models.GetUserByID(config.DB, patientID)
+	if err != nil || patient.Role != "pasien" {
+		http.Error(w, "Pasien tidak ditemukan", http.StatusNotFound)
+		return
+	}
+
+	now := config.Now()
+	doctors, _, err := doctorChoices(0, now.Format("2006-01-02"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	found := false
+	for _, d := range doctors {
+		found = found || d.UserID == doctorID
+	}
+	if !found {
+		renderWalkInForm(w, r, "Dokter tidak tersedia hari ini")
+		return
+	}
+
+	nomorReg, err := newNomorRegistrasi()
+	if err != nil {
+		http.Error(w, "Gagal membuat nomor registrasi: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	appointmentID, _, err := models.CreateWalkInAppointment(config.DB, nomorReg, patientID, doctorID, now.Format("2006-01-02"))
+	if err == models.ErrAlreadyBookedToday {
+		renderWalkInForm(w, r, "Pasien sudah memiliki appointment aktif hari ini")
+		return
+	}
+	if err != nil {
+		http.Error(w, "Gagal daftar: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/walkin/tiket/"+strconv.Itoa(appointmentID), http.StatusSeeOther)
+}
+
+// AdminWalkInTiketPage - Tiket nomor antrian untuk dicetak/ditunjukkan ke pasien
+func AdminWalkInTiketPage(w http.ResponseWriter, r *http.Request) {
+	appointmentID, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	apt, err := models.GetAppointmentByID(config.DB, appointmentID)
+	if err != nil || !apt.NomorAntrian.Valid {
+		http.Error(w, "Tiket tidak ditemukan", http.StatusNotFound)
+		return
+	}
+
+	// Pasien loket yang belum punya akun online mendapat kode aktivasi di tiket
+	kodeAktivasi, err := models.GetOrCreateActivationCode(config.DB, apt.PatientID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]interface{}{
+		"Appointment":  apt,
+		"ClinicName":   config.ClinicName(),
+		"KodeAktivasi": kodeAktivasi,
+	}
+
+	tmpl, err := template.ParseFiles("templates/admin_walkin_tiket.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tmpl.Execute(w, data)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"html/template"
+	"klinik-app/config"
+	"klinik-app/events"
+	"klinik-app/models"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// AdminWebhooksPage - Daftar webhook keluar beserta log pengiriman terbaru
+func AdminWebhooksPage(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := models.GetWebhooks(config.DB)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	deliveries, err := models.GetRecentWebhookDeliveries(config.DB, 100)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]interface{}{
+		"Webhooks":   webhooks,
+		"Deliveries": deliveries,
+		"Events":     events.All,
+	}
+
+	tmpl, err := template.ParseFiles("templates/admin_webhooks.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tmpl.Execute(w, data)
+}
+
+// AdminWebhookCreateHandler - Daftarkan URL webhook baru
+func AdminWebhookCreateHandler(w http.ResponseWriter, r *http.Request) {
+	target := strings.TrimSpace(r.FormValue("url"))
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		http.Error(w, "URL webhook harus diawali http:// atau https://", http.StatusBadRequest)
+		return
+	}
+
+	eventList := "*"
+	if selected := r.Form["events"]; len(selected) > 0 && len(selected) < len(events.All) {
+		for _, e := range selected {
+			valid := false
+			for _, known := range events.All {
+				valid = valid || e == known
+			}
+			if !valid {
+				http.Error(w, "Event tidak dikenal: "+e, http.StatusBadRequest)
+				return
+			}
+		}
+		eventList = strings.Join(selected, ",")
+	}
+
+	if err := models.CreateWebhook(config.DB, target, eventList); err != nil {
+		http.Error(w, "Gagal simpan webhook: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/webhooks", http.StatusSeeOther)
+}
+
+// AdminWebhookToggleHandler - Aktifkan/nonaktifkan webhook
+func AdminWebhookToggleHandler(w http.ResponseWriter, r *http.Request) {
+	webhookID, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	if err := models.SetWebhookActive(config.DB, webhookID, r.FormValue("aktif") == "1"); err != nil {
+		http.Error(w, "Gagal update webhook: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/webhooks", http.StatusSeeOther)
+}
+
+// AdminWebhookDeleteHandler - Hapus webhook
+func AdminWebhookDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	webhookID, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	if err := models.DeleteWebhook(config.DB, webhookID); err != nil {
+		http.Error(w, "Gagal hapus webhook: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/webhooks", http.StatusSeeOther)
+}
+
+// AdminWebhookRetryHandler - Jadwalkan ulang pengiriman yang sudah failed
+func AdminWebhookRetryHandler(w http.ResponseWriter, r *http.Request) {
+	deliveryID, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	if err := models.RetryWebhookDelivery(config.DB, deliveryID); err != nil {
+		http.Error(w, "Gagal kirim ulang: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/webhooks", http.StatusSeeOther)
+}
@@ -7,6 +7,7 @@ import (
 	"klinik-app/models"
 	"log"
 	"net/http"
+	"strings"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -69,6 +70,8 @@ func LoginHandler(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/admin/dashboard", http.StatusSeeOther)
 	case "dokter":
 		http.Redirect(w, r, "/dokter/dashboard", http.StatusSeeOther)
+	case "lab":
+		http.Redirect(w, r, "/lab/dashboard", http.StatusSeeOther)
 	default:
 		http.Error(w, "Role tidak dikenali", http.StatusForbidden)
 	}
@@ -101,6 +104,8 @@ func RegisterHandler(w http.ResponseWriter, r *http.Request) {
 
 	nik := r.FormValue("nik")
 	nama := r.FormValue("nama")
+	email := strings.TrimSpace(r.FormValue("email"))
+	noHP := strings.TrimSpace(r.FormValue("no_hp"))
 	password := r.FormValue("password")
 	confirmPassword := r.FormValue("confirm_password")
 
@@ -121,11 +126,19 @@ func RegisterHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Cek NIK sudah terdaftar atau belum
+	// Pasien yang didaftarkan di loket (tanpa password) boleh melengkapi akunnya di sini
+	// dengan kode aktivasi yang tercetak di tiket loket
 	existingUser, _ := models.GetUserByNIK(config.DB, nik)
-	if existingUser != nil {
+	if existingUser != nil && (existingUser.Role != "pasien" || existingUser.Password != "") {
 		http.Error(w, "NIK sudah terdaftar. Silakan login.", http.StatusConflict)
 		return
 	}
+	kodeAktivasi := strings.TrimSpace(r.FormValue("kode_aktivasi"))
+	if existingUser != nil && kodeAktivasi == "" {
+		http.Error(w, "NIK sudah terdaftar di loket klinik. Isi kode aktivasi yang tercetak di tiket, "+
+			"atau minta kode ke petugas loket.", http.StatusConflict)
+		return
+	}
 
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -134,11 +147,19 @@ func RegisterHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Insert user baru dengan role pasien
-	_, err = config.DB.Exec(
-		"INSERT INTO users (nik, nama, password, role) VALUES (?, ?, ?, 'pasien')",
-		nik, nama, string(hashedPassword),
-	)
+	// Insert user baru dengan role pasien (email & no HP opsional untuk notifikasi)
+	if existingUser == nil {
+		_, err = config.DB.Exec(
+			"INSERT INTO users (nik, nama, password, role, email, no_hp) VALUES (?, ?, ?, 'pasien', NULLIF(?, ''), NULLIF(?, ''))",
+			nik, nama, string(hashedPassword), email, noHP,
+		)
+	} else {
+		err = models.ActivateWalkInAccount(config.DB, existingUser.UserID, kodeAktivasi, nama, string(hashedPassword), email, noHP)
+		if err == models.ErrInvalidActivationCode {
+			http.Error(w, "Kode aktivasi tidak sesuai. Periksa tiket loket atau minta kode ke petugas loket.", http.StatusConflict)
+			return
+		}
+	}
 
 	if err != nil {
 		log.Printf("❌ Registration failed: %v", err)
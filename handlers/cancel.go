@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"html/template"
+	"klinik-app/config"
+	"klinik-app/middleware"
+	"klinik-app/models"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// alasanLainnya - Pilihan alasan bebas; keterangan wajib diisi
+const alasanLainnya = "Lainnya"
+
+// cancelReason - Alasan (dari CANCEL_REASONS atau "Lainnya") dan keterangan dari form; pesan error jika tidak valid
+func cancelReason(r *http.Request) (alasan, keterangan, errMsg string) {
+	alasan = r.FormValue("alasan")
+	keterangan = strings.TrimSpace(r.FormValue("keterangan"))
+	if len(keterangan) > 255 {
+		return "", "", "Keterangan maksimal 255 karakter"
+	}
+
+	if alasan == alasanLainnya {
+		if keterangan == "" {
+			return "", "", "Keterangan wajib diisi untuk alasan Lainnya"
+		}
+		return alasan, keterangan, ""
+	}
+	for _, reason := range config.CancelReasons() {
+		if alasan == reason {
+			return alasan, keterangan, ""
+		}
+	}
+	return "", "", "Pilih alasan pembatalan"
+}
+
+// renderCancelForm - Form alasan pembatalan untuk pasien maupun admin
+func renderCancelForm(w http.ResponseWriter, apt *models.Appointment, action, back, errMsg string) {
+	data := map[string]interface{}{
+		"Appointment": apt,
+		"Action":      action,
+		"Back":        back,
+		"Reasons":     append(config.CancelReasons(), alasanLainnya),
+		"Error":       errMsg,
+	}
+
+	tmpl, err := template.ParseFiles("templates/cancel.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if errMsg != "" {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	tmpl.Execute(w, data)
+}
+
+// PasienCancelPage - Form pembatalan appointment milik pasien
+func PasienCancelPage(w http.ResponseWriter, r *http.Request) {
+	renderCancelForm(w, middleware.GetAppointment(r), "/pasien/cancel-appointment", "/pasien/dashboard", "")
+}
+
+// AdminCancelPage - Form pembatalan appointment oleh admin
+func AdminCancelPage(w http.ResponseWriter, r *http.Request) {
+	appointmentID, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	apt, err := models.GetAppointmentByID(config.DB, appointmentID)
+	if err != nil {
+		http.Error(w, "Appointment tidak ditemukan", http.StatusNotFound)
+		return
+	}
+
+	renderCancelForm(w, apt, "/admin/cancel-appointment", "/admin/dashboard", "")
+}
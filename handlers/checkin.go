@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"database/sql"
+	"html/template"
+	"klinik-app/config"
+	"klinik-app/middleware"
+	"klinik-app/models"
+	"klinik-app/qr"
+	"klinik-app/regno"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// AdminCheckinPage - Check-in di loket: cari appointment dari nomor registrasi yang diketik atau di-scan (QR)
+func AdminCheckinPage(w http.ResponseWriter, r *http.Request) {
+	nomor := regno.Normalize(r.URL.Query().Get("nomor"))
+	today := config.Now().Format("2006-01-02")
+
+	data := map[string]interface{}{
+		"Nomor":    nomor,
+		"Today":    today,
+		"Berhasil": r.URL.Query().Get("ok") == "1",
+	}
+
+	if nomor != "" {
+		// Nomor format lama (sebelum ada digit cek) tetap dicari apa adanya
+		if regno.Verify(config.RegistrationFormat(), nomor) == regno.ErrCheckDigit {
+			data["Error"] = regno.ErrCheckDigit.Error()
+		} else {
+			apt, err := models.GetAppointmentByNomorReg(config.DB, nomor)
+			switch {
+			case err == sql.ErrNoRows:
+				data["Error"] = "Nomor registrasi " + nomor + " tidak ditemukan"
+			case err != nil:
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			default:
+				data["Appointment"] = apt
+				data["Tanggal"] = apt.TanggalKonsultasi.Format("2006-01-02")
+			}
+		}
+	}
+
+	tmpl, err := template.ParseFiles("templates/admin_checkin.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tmpl.Execute(w, data)
+}
+
+// AdminCheckinHandler - Catat kedatangan pasien; nomor antrian diberikan jika belum punya
+func AdminCheckinHandler(w http.ResponseWriter, r *http.Request) {
+	sess := middleware.GetSession(r)
+	appointmentID, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	apt, err := models.GetAppointmentByID(config.DB, appointmentID)
+	if err != nil {
+		http.Error(w, "Appointment tidak ditemukan", http.StatusNotFound)
+		return
+	}
+
+	_, err = models.CheckInAppointment(config.DB, appointmentID, sess["UserID"].(int), config.Now().Format("2006-01-02"))
+	if err == models.ErrNotCheckable {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Gagal check-in: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/checkin?ok=1&nomor="+url.QueryEscape(apt.NomorRegistrasi), http.StatusSeeOther)
+}
+
+// AppointmentQR - QR code berisi nomor registrasi untuk ditunjukkan pasien saat check-in
+func AppointmentQR(w http.ResponseWriter, r *http.Request) {
+	apt := middleware.GetAppointment(r)
+
+	code, err := qr.Encode(apt.NomorRegistrasi)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+	w.Write([]byte(code.SVG()))
+}
@@ -1,30 +1,77 @@
 package handlers
 
 import (
+	"fmt"
 	"html/template"
 	"klinik-app/config"
 	"klinik-app/middleware"
 	"klinik-app/models"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 )
 
-// DokterDashboard - Dashboard untuk dokter
+// DokterDashboard - Dashboard dokter: agenda per hari/minggu/bulan, appointment mendatang & riwayat.
+// Query: view (default hari) & tanggal (YYYY-MM-DD, default hari ini di zona waktu klinik)
 func DokterDashboard(w http.ResponseWriter, r *http.Request) {
 	sess := middleware.GetSession(r)
+	doctorID := sess["UserID"].(int)
 
-	// Get appointment hari ini
-	appointments, err := models.GetTodayAppointmentsByDoctor(config.DB, sess["UserID"].(int))
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	now := config.Now()
+	today := now.Format("2006-01-02")
+
+	view := r.URL.Query().Get("view")
+	switch view {
+	case "hari", "minggu", "bulan", "mendatang", "riwayat":
+	default:
+		view = "hari"
 	}
 
+	t := agendaDate(r.URL.Query().Get("tanggal"), now)
+
 	data := map[string]interface{}{
-		"Nama":         sess["Nama"],
-		"Appointments": appointments,
+		"Nama":    sess["Nama"],
+		"Unread":  unreadCount(sess),
+		"View":    view,
+		"Views":   agendaViews,
+		"Today":   today,
+		"Tanggal": t.Format("2006-01-02"),
+	}
+
+	switch view {
+	case "mendatang", "riwayat":
+		var appointments []models.Appointment
+		var err error
+		if view == "mendatang" {
+			appointments, err = models.GetDoctorUpcomingAppointments(config.DB, doctorID, today, agendaListLimit)
+			data["Judul"] = "Appointment Mendatang"
+		} else {
+			appointments, err = models.GetDoctorPastAppointments(config.DB, doctorID, today, agendaListLimit)
+			data["Judul"] = "Riwayat Appointment"
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		data["Days"] = groupAgenda(appointments, today)
+		data["Limit"] = agendaListLimit
+		data["Truncated"] = len(appointments) == agendaListLimit
+
+	default:
+		dari, sampai, prev, next, judul := agendaRange(view, t)
+		appointments, err := models.GetDoctorAppointmentsBetween(config.DB, doctorID,
+			dari.Format("2006-01-02"), sampai.Format("2006-01-02"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		data["Days"] = buildAgenda(appointments, dari, sampai, t.Month(), today)
+		data["Judul"] = judul
+		data["Prev"] = prev.Format("2006-01-02")
+		data["Next"] = next.Format("2006-01-02")
+		data["Total"] = len(appointments)
 	}
 
 	tmpl, err := template.ParseFiles("templates/dokter_dashboard.html")
@@ -35,13 +82,85 @@ func DokterDashboard(w http.ResponseWriter, r *http.Request) {
 	tmpl.Execute(w, data)
 }
 
-// DokterKonsultasiPage - Form input hasil konsultasi
-func DokterKonsultasiPage(w http.ResponseWriter, r *http.Request) {
+// getDokterAppointment - Ambil appointment dari URL dan pastikan ditugaskan ke dokter yang login
+func getDokterAppointment(w http.ResponseWriter, r *http.Request) (*models.Appointment, bool) {
+	sess := middleware.GetSession(r)
 	vars := mux.Vars(r)
-	appointmentID := vars["id"]
+	appointmentID, _ := strconv.Atoi(vars["id"])
+
+	apt, err := models.GetAppointmentByID(config.DB, appointmentID)
+	if err != nil {
+		http.Error(w, "Appointment tidak ditemukan", http.StatusNotFound)
+		return nil, false
+	}
+
+	if !apt.DoctorID.Valid || int(apt.DoctorID.Int64) != sess["UserID"].(int) {
+		http.Error(w, "Forbidden - Appointment ini bukan pasien Anda", http.StatusForbidden)
+		return nil, false
+	}
+
+	return apt, true
+}
+
+// DokterKonsultasiPage - Form input hasil konsultasi beserta profil & riwayat pasien
+func DokterKonsultasiPage(w http.ResponseWriter, r *http.Request) {
+	apt, ok := getDokterAppointment(w, r)
+	if !ok {
+		return
+	}
+
+	// Konsultasi yang sudah selesai hanya bisa dikoreksi lewat amandemen
+	if apt.Status == "completed" {
+		http.Redirect(w, r, fmt.Sprintf("/dokter/konsultasi/%d/amandemen", apt.AppointmentID), http.StatusSeeOther)
+		return
+	}
+
+	pasien, err := models.GetUserByID(config.DB, apt.PatientID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	history, err := models.GetPatientHistory(config.DB, apt.PatientID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Hanya kunjungan yang sudah selesai, selain appointment saat ini
+	var riwayat []models.Appointment
+	for _, h := range history {
+		if h.Status == "completed" && h.AppointmentID != apt.AppointmentID {
+			riwayat = append(riwayat, h)
+		}
+	}
+
+	labOrders, err := models.GetLabOrdersByPatient(config.DB, apt.PatientID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	attachments, err := models.GetAttachmentsByAppointment(config.DB, apt.AppointmentID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Tren tanda vital diurutkan dari kunjungan terlama
+	var tren []models.Appointment
+	for i := len(riwayat) - 1; i >= 0; i-- {
+		tren = append(tren, riwayat[i])
+	}
 
 	data := map[string]interface{}{
-		"AppointmentID": appointmentID,
+		"AppointmentID": apt.AppointmentID,
+		"Appointment":   apt,
+		"Pasien":        pasien,
+		"Riwayat":       riwayat,
+		"TrenVital":     tren,
+		"LabOrders":     labOrders,
+		"Attachments":   attachments,
 	}
 
 	tmpl, err := template.ParseFiles("templates/dokter_konsultasi.html")
@@ -52,6 +171,19 @@ func DokterKonsultasiPage(w http.ResponseWriter, r *http.Request) {
 	tmpl.Execute(w, data)
 }
 
+// hasilKonsultasiFromForm - Ambil isian form konsultasi
+func hasilKonsultasiFromForm(r *http.Request) models.HasilKonsultasi {
+	return models.HasilKonsultasi{
+		Gejala:       r.FormValue("gejala"),
+		Diagnosa:     r.FormValue("diagnosa"),
+		Resep:        r.FormValue("resep"),
+		TekananDarah: r.FormValue("tekanan_darah"),
+		Suhu:         r.FormValue("suhu"),
+		BeratBadan:   r.FormValue("berat_badan"),
+		Nadi:         r.FormValue("nadi"),
+	}
+}
+
 // DokterKonsultasiHandler - Proses input hasil konsultasi
 func DokterKonsultasiHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
@@ -59,14 +191,23 @@ func DokterKonsultasiHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	vars := mux.Vars(r)
-	appointmentID, _ := strconv.Atoi(vars["id"])
-	gejala := r.FormValue("gejala")
-	diagnosa := r.FormValue("diagnosa")
-	resep := r.FormValue("resep")
+	apt, ok := getDokterAppointment(w, r)
+	if !ok {
+		return
+	}
+
+	if apt.Status != "approved" && apt.Status != "in_progress" {
+		http.Error(w, "Konsultasi tidak bisa diisi (status: "+apt.Status+")", http.StatusBadRequest)
+		return
+	}
 
 	// Update appointment dengan hasil konsultasi
-	err := models.CompleteConsultation(config.DB, appointmentID, gejala, diagnosa, resep)
+	sess := middleware.GetSession(r)
+	err := models.CompleteConsultation(config.DB, apt.AppointmentID, sess["UserID"].(int), hasilKonsultasiFromForm(r))
+	if err == models.ErrConsultationClosed {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Gagal simpan: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -74,3 +215,146 @@ func DokterKonsultasiHandler(w http.ResponseWriter, r *http.Request) {
 
 	http.Redirect(w, r, "/dokter/dashboard", http.StatusSeeOther)
 }
+
+// DokterDraftHandler - Simpan draft konsultasi (dipanggil autosave atau tombol Simpan Draft)
+func DokterDraftHandler(w http.ResponseWriter, r *http.Request) {
+	apt, ok := getDokterAppointment(w, r)
+	if !ok {
+		return
+	}
+
+	if apt.Status != "approved" && apt.Status != "in_progress" {
+		http.Error(w, "Draft tidak bisa disimpan (status: "+apt.Status+")", http.StatusBadRequest)
+		return
+	}
+
+	err := models.SaveConsultationDraft(config.DB, apt.AppointmentID, hasilKonsultasiFromForm(r))
+	if err == models.ErrConsultationClosed {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Gagal simpan draft: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Autosave dari JavaScript tidak perlu redirect
+	if r.Header.Get("X-Requested-With") == "fetch" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	http.Redirect(w, r, "/dokter/dashboard", http.StatusSeeOther)
+}
+
+// DokterAmandemenPage - Form koreksi hasil konsultasi beserta riwayat versinya
+func DokterAmandemenPage(w http.ResponseWriter, r *http.Request) {
+	apt, ok := getDokterAppointment(w, r)
+	if !ok {
+		return
+	}
+
+	if apt.Status != "completed" {
+		http.Redirect(w, r, fmt.Sprintf("/dokter/konsultasi/%d", apt.AppointmentID), http.StatusSeeOther)
+		return
+	}
+
+	versions, err := models.GetConsultationVersions(config.DB, apt.AppointmentID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	suratSakit, _ := models.GetDocument(config.DB, apt.AppointmentID, models.DokumenSuratSakit)
+
+	data := map[string]interface{}{
+		"Appointment": apt,
+		"Versions":    versions,
+		"SuratSakit":  suratSakit,
+	}
+
+	tmpl, err := template.ParseFiles("templates/dokter_amandemen.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tmpl.Execute(w, data)
+}
+
+// DokterAmandemenHandler - Proses koreksi hasil konsultasi
+func DokterAmandemenHandler(w http.ResponseWriter, r *http.Request) {
+	apt, ok := getDokterAppointment(w, r)
+	if !ok {
+		return
+	}
+
+	if apt.Status != "completed" {
+		http.Error(w, "Hanya konsultasi yang sudah selesai yang bisa diamandemen", http.StatusBadRequest)
+		return
+	}
+
+	alasan := strings.TrimSpace(r.FormValue("alasan"))
+	if alasan == "" {
+		http.Error(w, "Alasan amandemen wajib diisi", http.StatusBadRequest)
+		return
+	}
+
+	sess := middleware.GetSession(r)
+	err := models.AmendConsultation(config.DB, apt.AppointmentID, sess["UserID"].(int), hasilKonsultasiFromForm(r), alasan)
+	if err != nil {
+		http.Error(w, "Gagal simpan amandemen: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/dokter/konsultasi/%d/amandemen", apt.AppointmentID), http.StatusSeeOther)
+}
+
+// DokterLabOrderHandler - Dokter membuat order pemeriksaan lab/radiologi dari form konsultasi
+func DokterLabOrderHandler(w http.ResponseWriter, r *http.Request) {
+	apt, ok := getDokterAppointment(w, r)
+	if !ok {
+		return
+	}
+
+	// Order hanya selama konsultasi belum selesai/dibatalkan
+	if apt.Status != "approved" && apt.Status != "in_progress" {
+		http.Error(w, "Order lab/radiologi hanya bisa dibuat untuk appointment yang sedang berjalan", http.StatusConflict)
+		return
+	}
+
+	jenis := r.FormValue("jenis")
+	pemeriksaan := strings.TrimSpace(r.FormValue("pemeriksaan"))
+	if jenis != "lab" && jenis != "radiologi" {
+		http.Error(w, "Jenis pemeriksaan tidak valid", http.StatusBadRequest)
+		return
+	}
+	if pemeriksaan == "" {
+		http.Error(w, "Nama pemeriksaan wajib diisi", http.StatusBadRequest)
+		return
+	}
+
+	sess := middleware.GetSession(r)
+	err := models.CreateLabOrder(config.DB, apt.AppointmentID, apt.PatientID, sess["UserID"].(int),
+		jenis, pemeriksaan, r.FormValue("catatan"))
+	if err != nil {
+		http.Error(w, "Gagal membuat order: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/dokter/konsultasi/%d", apt.AppointmentID), http.StatusSeeOther)
+}
+
+// DokterNoShowHandler - Dokter menandai pasien tidak hadir
+func DokterNoShowHandler(w http.ResponseWriter, r *http.Request) {
+	apt, ok := getDokterAppointment(w, r)
+	if !ok {
+		return
+	}
+
+	if err := models.MarkNoShow(config.DB, apt.AppointmentID, int(apt.DoctorID.Int64), config.Now().Format("2006-01-02")); err != nil {
+		http.Error(w, "Gagal menandai tidak hadir: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	http.Redirect(w, r, "/dokter/dashboard", http.StatusSeeOther)
+}
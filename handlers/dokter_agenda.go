@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"fmt"
+	"klinik-app/booking"
+	"klinik-app/models"
+	"time"
+)
+
+// agendaViews - Tampilan dashboard dokter yang tersedia
+var agendaViews = []struct{ Kode, Label string }{
+	{"hari", "Hari"},
+	{"minggu", "Minggu"},
+	{"bulan", "Bulan"},
+	{"mendatang", "Mendatang"},
+	{"riwayat", "Riwayat"},
+}
+
+// agendaListLimit - Jumlah appointment yang ditampilkan pada tampilan mendatang & riwayat
+const agendaListLimit = 50
+
+var namaBulan = [...]string{"", "Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+
+// hariAgenda - Satu tanggal pada agenda dokter beserta appointment-nya
+type hariAgenda struct {
+	Tanggal      string // YYYY-MM-DD
+	Label        string // misal "Senin, 19 Oktober 2026"
+	Tgl          int
+	HariIni      bool
+	Mendatang    bool // setelah hari ini; konsultasi belum bisa dimulai
+	BulanLain    bool // tanggal di luar bulan yang ditampilkan (tampilan bulan)
+	Appointments []models.Appointment
+}
+
+// labelTanggal - Format tanggal lengkap, misal "Senin, 19 Oktober 2026"
+func labelTanggal(t time.Time) string {
+	return fmt.Sprintf("%s, %d %s %d", booking.NamaHari(t.Weekday()), t.Day(), namaBulan[t.Month()], t.Year())
+}
+
+// awalMinggu - Senin pada minggu tanggal t
+func awalMinggu(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return t.AddDate(0, 0, -offset)
+}
+
+// agendaDate - Tanggal yang dibuka (YYYY-MM-DD dari query) sebagai tengah malam waktu klinik;
+// kosong/tidak valid berarti hari ini menurut now
+func agendaDate(tanggal string, now time.Time) time.Time {
+	t, err := time.ParseInLocation("2006-01-02", tanggal, now.Location())
+	if err != nil {
+		t = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	}
+	return t
+}
+
+// agendaRange - Rentang tanggal (inklusif) yang ditampilkan, beserta tanggal navigasi sebelum/sesudah
+// dan judul periodenya
+func agendaRange(view string, t time.Time) (dari, sampai, prev, next time.Time, judul string) {
+	switch view {
+	case "minggu":
+		dari = awalMinggu(t)
+		sampai = dari.AddDate(0, 0, 6)
+		prev, next = t.AddDate(0, 0, -7), t.AddDate(0, 0, 7)
+		judul = fmt.Sprintf("%d %s – %d %s %d", dari.Day(), namaBulan[dari.Month()],
+			sampai.Day(), namaBulan[sampai.Month()], sampai.Year())
+	case "bulan":
+		// Grid kalender dimulai Senin & diakhiri Minggu
+		awal := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+		akhir := awal.AddDate(0, 1, -1)
+		dari = awalMinggu(awal)
+		sampai = awalMinggu(akhir).AddDate(0, 0, 6)
+		prev, next = awal.AddDate(0, -1, 0), awal.AddDate(0, 1, 0)
+		judul = fmt.Sprintf("%s %d", namaBulan[t.Month()], t.Year())
+	default:
+		dari, sampai = t, t
+		prev, next = t.AddDate(0, 0, -1), t.AddDate(0, 0, 1)
+		judul = labelTanggal(t)
+	}
+	return
+}
+
+// buildAgenda - Kelompokkan appointment per tanggal untuk setiap hari dari s/d sampai
+func buildAgenda(appointments []models.Appointment, dari, sampai time.Time, bulan time.Month, today string) []hariAgenda {
+	perTanggal := make(map[string][]models.Appointment)
+	for _, apt := range appointments {
+		key := apt.TanggalKonsultasi.Format("2006-01-02")
+		perTanggal[key] = append(perTanggal[key], apt)
+	}
+
+	var days []hariAgenda
+	for d := dari; !d.After(sampai); d = d.AddDate(0, 0, 1) {
+		key := d.Format("2006-01-02")
+		days = append(days, hariAgenda{
+			Tanggal:      key,
+			Label:        labelTanggal(d),
+			Tgl:          d.Day(),
+			HariIni:      key == today,
+			Mendatang:    key > today,
+			BulanLain:    d.Month() != bulan,
+			Appointments: perTanggal[key],
+		})
+	}
+	return days
+}
+
+// groupAgenda - Kelompokkan daftar appointment (sudah terurut) per tanggal, hanya tanggal yang ada appointment-nya
+func groupAgenda(appointments []models.Appointment, today string) []hariAgenda {
+	var days []hariAgenda
+	for _, apt := range appointments {
+		key := apt.TanggalKonsultasi.Format("2006-01-02")
+		if n := len(days); n > 0 && days[n-1].Tanggal == key {
+			days[n-1].Appointments = append(days[n-1].Appointments, apt)
+			continue
+		}
+		days = append(days, hariAgenda{
+			Tanggal:      key,
+			Label:        labelTanggal(apt.TanggalKonsultasi),
+			Tgl:          apt.TanggalKonsultasi.Day(),
+			HariIni:      key == today,
+			Mendatang:    key > today,
+			Appointments: []models.Appointment{apt},
+		})
+	}
+	return days
+}
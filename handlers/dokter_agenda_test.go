@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+func jakarta(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestAgendaDate(t *testing.T) {
+	loc := jakarta(t)
+	now := time.Date(2026, 10, 18, 17, 30, 0, 0, time.UTC).In(loc) // 00:30 WIB tanggal 19
+
+	tests := []struct {
+		tanggal string
+		want    string
+	}{
+		{"", "2026-10-19"},
+		{"bukan-tanggal", "2026-10-19"},
+		{"2026-10-25", "2026-10-25"},
+		{"2026-12-31", "2026-12-31"},
+	}
+
+	for _, tt := range tests {
+		got := agendaDate(tt.tanggal, now)
+		if got.Format("2006-01-02") != tt.want {
+			t.Errorf("agendaDate(%q) = %s, want %s", tt.tanggal, got.Format("2006-01-02"), tt.want)
+		}
+		if got.Location() != loc || got.Hour() != 0 || got.Minute() != 0 {
+			t.Errorf("agendaDate(%q) = %s, want midnight in %s", tt.tanggal, got, loc)
+		}
+	}
+}
+
+func TestAwalMinggu(t *testing.T) {
+	loc := jakarta(t)
+
+	tests := []struct {
+		tanggal string
+		want    string
+	}{
+		{"2026-10-19", "2026-10-19"}, // Senin
+		{"2026-10-18", "2026-10-12"}, // Minggu ikut minggu sebelumnya
+		{"2026-10-01", "2026-09-28"},
+		{"2026-11-01", "2026-10-26"},
+		{"2026-12-31", "2026-12-28"},
+	}
+
+	for _, tt := range tests {
+		got := awalMinggu(agendaDate(tt.tanggal, time.Now().In(loc)))
+		if got.Format("2006-01-02") != tt.want {
+			t.Errorf("awalMinggu(%s) = %s, want %s", tt.tanggal, got.Format("2006-01-02"), tt.want)
+		}
+	}
+}
+
+func TestAgendaRange(t *testing.T) {
+	loc := jakarta(t)
+
+	tests := []struct {
+		view, tanggal            string
+		dari, sampai, prev, next string
+		judul                    string
+	}{
+		{"hari", "2026-10-19", "2026-10-19", "2026-10-19", "2026-10-18", "2026-10-20", "Senin, 19 Oktober 2026"},
+		{"minggu", "2026-10-19", "2026-10-19", "2026-10-25", "2026-10-12", "2026-10-26", "19 Oktober – 25 Oktober 2026"},
+		{"minggu", "2026-10-18", "2026-10-12", "2026-10-18", "2026-10-11", "2026-10-25", "12 Oktober – 18 Oktober 2026"},
+		{"minggu", "2026-12-31", "2026-12-28", "2027-01-03", "2026-12-24", "2027-01-07", "28 Desember – 3 Januari 2027"},
+		{"bulan", "2026-10-19", "2026-09-28", "2026-11-01", "2026-09-01", "2026-11-01", "Oktober 2026"},
+		{"bulan", "2026-10-31", "2026-09-28", "2026-11-01", "2026-09-01", "2026-11-01", "Oktober 2026"},
+	}
+
+	format := func(t time.Time) string { return t.Format("2006-01-02") }
+	for _, tt := range tests {
+		// Tanggal dibuka dari dashboard pada 00:30 WIB (17:30 UTC hari sebelumnya)
+		now := time.Date(2026, 10, 18, 17, 30, 0, 0, time.UTC).In(loc)
+		dari, sampai, prev, next, judul := agendaRange(tt.view, agendaDate(tt.tanggal, now))
+
+		got := []string{format(dari), format(sampai), format(prev), format(next), judul}
+		want := []string{tt.dari, tt.sampai, tt.prev, tt.next, tt.judul}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Errorf("agendaRange(%s, %s) = %v, want %v", tt.view, tt.tanggal, got, want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"fmt"
+	"html/template"
+	"klinik-app/config"
+	"klinik-app/middleware"
+	"klinik-app/models"
+	"klinik-app/pdf"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	pdfMarginX = 50.0
+	pdfWidth   = pdf.PageWidth - 2*pdfMarginX
+)
+
+// pdfWriter - Penulis baris demi baris dengan pindah halaman otomatis
+type pdfWriter struct {
+	doc *pdf.Document
+	y   float64
+}
+
+// breakPage - Pindah ke halaman baru jika baris berikutnya melewati batas bawah
+func (p *pdfWriter) breakPage() {
+	if p.y > pdf.PageHeight-90 {
+		p.doc.AddPage()
+		p.y = 60
+	}
+}
+
+func (p *pdfWriter) line(size float64, bold bool, text string) {
+	p.breakPage()
+	p.doc.Text(pdfMarginX, p.y, size, bold, text)
+	p.y += size * 1.5
+}
+
+func (p *pdfWriter) paragraph(size float64, text string) {
+	for _, l := range pdf.Wrap(text, size, pdfWidth) {
+		p.line(size, false, l)
+	}
+}
+
+func (p *pdfWriter) field(label, value string) {
+	p.line(10, true, label)
+	if strings.TrimSpace(value) == "" {
+		value = "-"
+	}
+	p.paragraph(11, value)
+	p.y += 6
+}
+
+// newPDF - Dokumen dengan kop klinik dan judul
+func newPDF(judul string) *pdfWriter {
+	p := &pdfWriter{doc: pdf.New(), y: 55}
+	p.line(16, true, config.ClinicName())
+	if addr := config.ClinicAddress(); addr != "" {
+		p.line(10, false, addr)
+	}
+	p.doc.Line(pdfMarginX, p.y, pdf.PageWidth-pdfMarginX, p.y)
+	p.y += 30
+
+	size := 14.0
+	x := (pdf.PageWidth - float64(len(judul))*size*0.55) / 2
+	p.doc.Text(x, p.y, size, true, judul)
+	p.y += 35
+	return p
+}
+
+// footer - Kode verifikasi di bagian bawah halaman terakhir
+func (p *pdfWriter) footer(doc *models.Document) {
+	y := pdf.PageHeight - 60
+	p.doc.Line(pdfMarginX, y-14, pdf.PageWidth-pdfMarginX, y-14)
+	p.doc.Text(pdfMarginX, y, 9, true, "Kode Verifikasi: "+doc.KodeVerifikasi)
+	p.doc.Text(pdfMarginX, y+13, 9, false,
+		"Keaslian dokumen dapat dicek di "+config.BaseURL()+"/verifikasi?kode="+doc.KodeVerifikasi)
+}
+
+// pair - Baris "label : nilai" dengan kolom nilai rata kiri
+func (p *pdfWriter) pair(label, value string) {
+	p.breakPage()
+	p.doc.Text(pdfMarginX, p.y, 11, false, label)
+	p.doc.Text(pdfMarginX+110, p.y, 11, false, ": "+value)
+	p.y += 11 * 1.5
+}
+
+func (p *pdfWriter) identitas(apt *models.Appointment, pasien, dokter *models.User) {
+	p.pair("No. Registrasi", apt.NomorRegistrasi)
+	p.pair("Nama Pasien", pasien.Nama)
+	p.pair("NIK", pasien.NIK)
+	p.pair("Tanggal", apt.TanggalKonsultasi.Format("02/01/2006"))
+	p.pair("Dokter", dokter.Nama)
+	p.y += 15
+}
+
+func (p *pdfWriter) tandaTangan(tanggal string, dokter *models.User) {
+	p.y += 25
+	if p.y > pdf.PageHeight-170 {
+		p.doc.AddPage()
+		p.y = 60
+	}
+	x := pdf.PageWidth - pdfMarginX - 180
+	p.doc.Text(x, p.y, 11, false, tanggal)
+	p.doc.Text(x, p.y+15, 11, false, "Dokter Pemeriksa,")
+	p.doc.Text(x, p.y+75, 11, true, dokter.Nama)
+	p.y += 90
+}
+
+func nullString(valid bool, s string) string {
+	if !valid {
+		return ""
+	}
+	return s
+}
+
+// DokumenPDF - Download PDF ringkasan konsultasi, resep, atau surat keterangan sakit
+func DokumenPDF(w http.ResponseWriter, r *http.Request) {
+	sess := middleware.GetSession(r)
+	apt := middleware.GetAppointment(r)
+	jenis := mux.Vars(r)["jenis"]
+
+	if apt.Status != "completed" {
+		http.Error(w, "Dokumen hanya tersedia untuk konsultasi yang sudah selesai", http.StatusBadRequest)
+		return
+	}
+
+	var doc *models.Document
+	var err error
+	switch jenis {
+	case models.DokumenRingkasan, models.DokumenResep:
+		doc, err = models.GetOrCreateDocument(config.DB, apt.AppointmentID, jenis, sess["UserID"].(int))
+	case models.DokumenSuratSakit:
+		doc, err = models.GetDocument(config.DB, apt.AppointmentID, jenis)
+		if err != nil {
+			http.Error(w, "Surat keterangan sakit belum diterbitkan dokter", http.StatusNotFound)
+			return
+		}
+	default:
+		http.Error(w, "Jenis dokumen tidak dikenal", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Gagal membuat dokumen: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	pasien, err := models.GetUserByID(config.DB, apt.PatientID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	dokter, err := models.GetUserByID(config.DB, int(apt.DoctorID.Int64))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var p *pdfWriter
+	switch jenis {
+	case models.DokumenRingkasan:
+		p = newPDF("RINGKASAN HASIL KONSULTASI")
+		p.identitas(apt, pasien, dokter)
+		vital := fmt.Sprintf("Tekanan darah %s, suhu %s °C, berat badan %s kg, nadi %s x/menit",
+			orDash(nullString(apt.TekananDarah.Valid, apt.TekananDarah.String)),
+			orDash(nullString(apt.Suhu.Valid, strconv.FormatFloat(apt.Suhu.Float64, 'f', -1, 64))),
+			orDash(nullString(apt.BeratBadan.Valid, strconv.FormatFloat(apt.BeratBadan.Float64, 'f', -1, 64))),
+			orDash(nullString(apt.Nadi.Valid, strconv.FormatInt(apt.Nadi.Int64, 10))))
+		p.field("Tanda Vital", vital)
+		p.field("Keluhan / Gejala", nullString(apt.Gejala.Valid, apt.Gejala.String))
+		p.field("Diagnosa", nullString(apt.Diagnosa.Valid, apt.Diagnosa.String))
+		p.field("Resep Obat", nullString(apt.ResepObat.Valid, apt.ResepObat.String))
+		p.tandaTangan(apt.TanggalKonsultasi.Format("02/01/2006"), dokter)
+
+	case models.DokumenResep:
+		p = newPDF("RESEP OBAT")
+		p.identitas(apt, pasien, dokter)
+		p.line(12, true, "R/")
+		p.y += 4
+		for _, l := range strings.Split(nullString(apt.ResepObat.Valid, apt.ResepObat.String), "\n") {
+			if strings.TrimSpace(l) != "" {
+				p.paragraph(11, "•  "+strings.TrimSpace(l))
+			}
+		}
+		p.tandaTangan(apt.TanggalKonsultasi.Format("02/01/2006"), dokter)
+
+	case models.DokumenSuratSakit:
+		p = newPDF("SURAT KETERANGAN SAKIT")
+		p.paragraph(11, "Yang bertanda tangan di bawah ini, dokter pemeriksa pada "+config.ClinicName()+
+			", menerangkan bahwa:")
+		p.y += 10
+		p.pair("Nama", pasien.Nama)
+		p.pair("NIK", pasien.NIK)
+		p.y += 10
+		p.paragraph(11, fmt.Sprintf("Berdasarkan hasil pemeriksaan pada tanggal %s, yang bersangkutan dalam keadaan "+
+			"sakit dan perlu beristirahat selama %d hari, terhitung mulai tanggal %s sampai dengan %s.",
+			apt.TanggalKonsultasi.Format("02/01/2006"), doc.HariIstirahat.Int64,
+			doc.TanggalMulai.Time.Format("02/01/2006"), doc.TanggalSelesai().Format("02/01/2006")))
+		p.y += 10
+		p.paragraph(11, "Demikian surat keterangan ini dibuat untuk dapat dipergunakan sebagaimana mestinya.")
+		p.tandaTangan(doc.CreatedAt.Format("02/01/2006"), dokter)
+	}
+	p.footer(doc)
+
+	fileName := fmt.Sprintf("%s-%s.pdf", jenis, apt.NomorRegistrasi)
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", fileName))
+	w.Write(p.doc.Bytes())
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// DokterSuratSakitHandler - Dokter terbitkan surat keterangan sakit dengan jumlah hari istirahat
+func DokterSuratSakitHandler(w http.ResponseWriter, r *http.Request) {
+	apt, ok := getDokterAppointment(w, r)
+	if !ok {
+		return
+	}
+
+	if apt.Status != "completed" {
+		http.Error(w, "Surat sakit hanya bisa diterbitkan setelah konsultasi selesai", http.StatusBadRequest)
+		return
+	}
+
+	hari, err := strconv.Atoi(r.FormValue("hari_istirahat"))
+	if err != nil || hari < 1 || hari > 30 {
+		http.Error(w, "Jumlah hari istirahat harus 1 - 30 hari", http.StatusBadRequest)
+		return
+	}
+
+	mulai := r.FormValue("tanggal_mulai")
+	if mulai == "" {
+		mulai = apt.TanggalKonsultasi.Format("2006-01-02")
+	}
+	if _, err := time.Parse("2006-01-02", mulai); err != nil {
+		http.Error(w, "Format tanggal mulai tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	sess := middleware.GetSession(r)
+	if err := models.IssueSickLeave(config.DB, apt.AppointmentID, sess["UserID"].(int), hari, mulai); err != nil {
+		http.Error(w, "Gagal menerbitkan surat sakit: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/appointment/%d/dokumen/%s", apt.AppointmentID, models.DokumenSuratSakit), http.StatusSeeOther)
+}
+
+// VerifikasiPage - Halaman publik untuk cek keaslian dokumen dari kode verifikasi
+func VerifikasiPage(w http.ResponseWriter, r *http.Request) {
+	kode := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("kode")))
+
+	data := map[string]interface{}{
+		"Kode": kode,
+	}
+
+	if kode != "" {
+		doc, err := models.GetDocumentByKode(config.DB, kode)
+		if err == nil {
+			data["Document"] = doc
+		}
+	}
+
+	tmpl, err := template.ParseFiles("templates/verifikasi.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tmpl.Execute(w, data)
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"klinik-app/config"
+	"klinik-app/ical"
+	"klinik-app/middleware"
+	"klinik-app/models"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// absoluteURL - URL lengkap untuk path (APP_URL jika diset, selain itu dari host request)
+func absoluteURL(r *http.Request, path string) string {
+	if base := config.BaseURL(); base != "" {
+		return base + path
+	}
+
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + path
+}
+
+// calendarUIDDomain - Domain tetap untuk UID event, supaya UID tidak berubah mengikuti host yang dipakai
+// saat feed diambil (kalender akan menganggapnya event baru dan menduplikasinya)
+const calendarUIDDomain = "klinik-app"
+
+// calendarEvent - Ubah appointment menjadi event kalender; tanpa jam menjadi event sehari penuh
+func calendarEvent(apt models.Appointment, role string) ical.Event {
+	e := ical.Event{
+		UID:       fmt.Sprintf("appointment-%d@%s", apt.AppointmentID, calendarUIDDomain),
+		Location:  config.ClinicName(),
+		Tentative: apt.Status == "pending",
+	}
+	if addr := config.ClinicAddress(); addr != "" {
+		e.Location += ", " + addr
+	}
+
+	if role == "dokter" {
+		e.Summary = "Konsultasi: " + apt.NamaPasien
+	} else {
+		e.Summary = "Konsultasi di " + config.ClinicName()
+	}
+
+	e.Description = "No. Registrasi: " + apt.NomorRegistrasi
+	if apt.NamaDokter != "" {
+		e.Description += "\nDokter: " + apt.NamaDokter
+	}
+	if apt.Status == "pending" {
+		e.Description += "\nStatus: menunggu persetujuan admin (jam belum ditentukan)"
+	}
+
+	if start, ok := apt.JadwalMulai(config.Location()); ok {
+		e.Start = start
+		e.End = start.Add(config.ConsultationDuration())
+	} else {
+		e.Start = apt.TanggalKonsultasi
+		e.AllDay = true
+	}
+
+	return e
+}
+
+func writeCalendar(w http.ResponseWriter, fileName, name string, calEvents []ical.Event, download bool) {
+	var buf bytes.Buffer
+	if err := ical.Write(&buf, name, calEvents); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	disposition := "inline"
+	if download {
+		disposition = "attachment"
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition, fileName))
+	w.Write(buf.Bytes())
+}
+
+// KalenderFeed - Feed iCalendar publik (diakses aplikasi kalender dengan token rahasia, tanpa login)
+func KalenderFeed(w http.ResponseWriter, r *http.Request) {
+	user, err := models.GetUserByCalendarToken(config.DB, mux.Vars(r)["token"])
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	appointments, err := models.GetCalendarAppointments(config.DB, user.UserID, user.Role, config.Now().Format("2006-01-02"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var calEvents []ical.Event
+	for _, apt := range appointments {
+		calEvents = append(calEvents, calendarEvent(apt, user.Role))
+	}
+
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	writeCalendar(w, "jadwal-klinik.ics", config.ClinicName()+" - "+user.Nama, calEvents, false)
+}
+
+// KalenderPage - Tampilkan URL feed kalender milik user (pasien/dokter)
+func KalenderPage(w http.ResponseWriter, r *http.Request) {
+	sess := middleware.GetSession(r)
+	if sess["Role"] != "pasien" && sess["Role"] != "dokter" {
+		http.Error(w, "Forbidden - Anda tidak punya akses", http.StatusForbidden)
+		return
+	}
+
+	token, err := models.GetOrCreateCalendarToken(config.DB, sess["UserID"].(int))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	feedURL := absoluteURL(r, "/kalender/"+token+".ics")
+	data := map[string]interface{}{
+		"Nama":      sess["Nama"],
+		"FeedURL":   feedURL,
+		"WebcalURL": "webcal://" + strings.SplitN(feedURL, "://", 2)[1],
+		"BackURL":   dashboardURL(sess["Role"]),
+	}
+
+	tmpl, err := template.ParseFiles("templates/kalender.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tmpl.Execute(w, data)
+}
+
+// KalenderResetHandler - Buat token baru jika URL feed bocor
+func KalenderResetHandler(w http.ResponseWriter, r *http.Request) {
+	sess := middleware.GetSession(r)
+	if sess["Role"] != "pasien" && sess["Role"] != "dokter" {
+		http.Error(w, "Forbidden - Anda tidak punya akses", http.StatusForbidden)
+		return
+	}
+
+	if _, err := models.ResetCalendarToken(config.DB, sess["UserID"].(int)); err != nil {
+		http.Error(w, "Gagal membuat URL baru: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/kalender", http.StatusSeeOther)
+}
+
+// AppointmentICS - Download satu appointment sebagai file .ics ("Tambahkan ke kalender")
+func AppointmentICS(w http.ResponseWriter, r *http.Request) {
+	sess := middleware.GetSession(r)
+	apt := middleware.GetAppointment(r)
+
+	if apt.Status != "pending" && apt.Status != "approved" {
+		http.Error(w, "Appointment ini tidak lagi terjadwal", http.StatusBadRequest)
+		return
+	}
+
+	role, _ := sess["Role"].(string)
+	event := calendarEvent(*apt, role)
+	writeCalendar(w, "konsultasi-"+apt.NomorRegistrasi+".ics", config.ClinicName(), []ical.Event{event}, true)
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"klinik-app/config"
+	"klinik-app/middleware"
+	"klinik-app/models"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// LabDashboard - Dashboard petugas lab (order yang menunggu hasil)
+func LabDashboard(w http.ResponseWriter, r *http.Request) {
+	sess := middleware.GetSession(r)
+
+	orders, err := models.GetPendingLabOrders(config.DB)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]interface{}{
+		"Nama":   sess["Nama"],
+		"Unread": unreadCount(sess),
+		"Orders": orders,
+	}
+
+	tmpl, err := template.ParseFiles("templates/lab_dashboard.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tmpl.Execute(w, data)
+}
+
+// LabOrderPage - Form input hasil pemeriksaan
+func LabOrderPage(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	orderID, _ := strconv.Atoi(vars["id"])
+
+	order, err := models.GetLabOrderByID(config.DB, orderID)
+	if err != nil {
+		http.Error(w, "Order tidak ditemukan", http.StatusNotFound)
+		return
+	}
+
+	data := map[string]interface{}{
+		"Order": order,
+	}
+
+	tmpl, err := template.ParseFiles("templates/lab_order.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tmpl.Execute(w, data)
+}
+
+// LabOrderHandler - Proses input hasil pemeriksaan (nilai numerik dan/atau file)
+func LabOrderHandler(w http.ResponseWriter, r *http.Request) {
+	sess := middleware.GetSession(r)
+	vars := mux.Vars(r)
+	orderID, _ := strconv.Atoi(vars["id"])
+
+	order, err := models.GetLabOrderByID(config.DB, orderID)
+	if err != nil {
+		http.Error(w, "Order tidak ditemukan", http.StatusNotFound)
+		return
+	}
+	if order.Status != "ordered" {
+		http.Error(w, "Hasil order ini sudah diinput", http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, config.MaxUploadSize+1<<20)
+	if err := r.ParseMultipartForm(config.MaxUploadSize); err != nil {
+		http.Error(w, "Ukuran file maksimal 10 MB", http.StatusBadRequest)
+		return
+	}
+
+	// Validasi semua baris nilai numerik sebelum disimpan
+	parameters := r.MultipartForm.Value["parameter"]
+	nilai := r.MultipartForm.Value["nilai"]
+	satuan := r.MultipartForm.Value["satuan"]
+	rujukanMin := r.MultipartForm.Value["rujukan_min"]
+	rujukanMax := r.MultipartForm.Value["rujukan_max"]
+
+	var hasil []models.LabResultInput
+	for i, p := range parameters {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		b := models.LabResultInput{Parameter: p, Nilai: formIndex(nilai, i), Satuan: formIndex(satuan, i),
+			RujukanMin: formIndex(rujukanMin, i), RujukanMax: formIndex(rujukanMax, i)}
+		for _, v := range []string{b.Nilai, b.RujukanMin, b.RujukanMax} {
+			if v == "" {
+				continue
+			}
+			if _, err := strconv.ParseFloat(v, 64); err != nil {
+				http.Error(w, "Nilai untuk "+p+" harus berupa angka", http.StatusBadRequest)
+				return
+			}
+		}
+		if b.Nilai == "" {
+			http.Error(w, "Nilai untuk "+p+" wajib diisi", http.StatusBadRequest)
+			return
+		}
+		hasil = append(hasil, b)
+	}
+
+	_, _, err = r.FormFile("file")
+	hasFile := err == nil
+
+	if len(hasil) == 0 && !hasFile {
+		http.Error(w, "Isi minimal satu nilai pemeriksaan atau upload file hasil", http.StatusBadRequest)
+		return
+	}
+
+	// File divalidasi & disimpan lebih dulu supaya hasil tidak tersimpan setengah jika file ditolak
+	if hasFile {
+		key, fileName, mimeType, _, ok := saveUpload(w, r, "file", fmt.Sprintf("lab/%d", order.OrderID))
+		if !ok {
+			return
+		}
+		label := strings.TrimSpace(r.FormValue("file_label"))
+		if label == "" {
+			label = order.Pemeriksaan
+		}
+		hasil = append(hasil, models.LabResultInput{Parameter: label, FilePath: key, FileName: fileName, MimeType: mimeType})
+	}
+
+	// Hasil & status order disimpan dalam satu transaksi; order yang sudah diselesaikan petugas lain ditolak
+	err = models.CompleteLabOrder(config.DB, order.OrderID, sess["UserID"].(int), hasil)
+	if err != nil {
+		if hasFile {
+			config.Files.Delete(hasil[len(hasil)-1].FilePath)
+		}
+		if errors.Is(err, models.ErrLabOrderClosed) {
+			http.Error(w, "Hasil order ini sudah diinput", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Gagal simpan hasil: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/lab/dashboard", http.StatusSeeOther)
+}
+
+// LabResultFile - Download file hasil pemeriksaan (petugas lab, dokter pasien, atau pasien sendiri)
+func LabResultFile(w http.ResponseWriter, r *http.Request) {
+	sess := middleware.GetSession(r)
+	vars := mux.Vars(r)
+	resultID, _ := strconv.Atoi(vars["id"])
+
+	result, err := models.GetLabResultByID(config.DB, resultID)
+	if err != nil || !result.FilePath.Valid {
+		http.Error(w, "File tidak ditemukan", http.StatusNotFound)
+		return
+	}
+
+	order, err := models.GetLabOrderByID(config.DB, result.OrderID)
+	if err != nil {
+		http.Error(w, "Order tidak ditemukan", http.StatusNotFound)
+		return
+	}
+
+	userID, _ := sess["UserID"].(int)
+	allowed := false
+	switch sess["Role"] {
+	case "lab":
+		allowed = true
+	case "pasien":
+		allowed = order.PatientID == userID
+	case "dokter":
+		allowed, err = models.IsDoctorOfPatient(config.DB, userID, order.PatientID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	if !allowed {
+		http.Error(w, "Forbidden - Anda tidak punya akses", http.StatusForbidden)
+		return
+	}
+
+	serveStoredFile(w, r, result.FilePath.String, result.FileName.String, result.MimeType.String, result.CreatedAt)
+}
+
+// formIndex - Ambil nilai ke-i dari field form berulang (kosong jika tidak ada)
+func formIndex(values []string, i int) string {
+	if i < len(values) {
+		return strings.TrimSpace(values[i])
+	}
+	return ""
+}
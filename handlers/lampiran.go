@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"fmt"
+	"html/template"
+	"klinik-app/config"
+	"klinik-app/middleware"
+	"klinik-app/models"
+	"klinik-app/storage"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// dashboardURL - Halaman dashboard sesuai role (untuk link kembali)
+func dashboardURL(role interface{}) string {
+	switch role {
+	case "pasien", "admin", "dokter", "lab":
+		return fmt.Sprintf("/%s/dashboard", role)
+	}
+	return "/"
+}
+
+// saveUpload - Validasi file dari form lalu simpan ke storage dengan key prefix/<unix-nano><ext>
+func saveUpload(w http.ResponseWriter, r *http.Request, field, prefix string) (key, fileName, mimeType string, size int64, ok bool) {
+	file, header, err := r.FormFile(field)
+	if err != nil {
+		http.Error(w, "File wajib dipilih", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	mimeType, ext, err := storage.Validate(file, header, config.MaxUploadSize, storage.DocumentTypes)
+	switch err {
+	case nil:
+	case storage.ErrTooLarge:
+		http.Error(w, "Ukuran file maksimal 10 MB", http.StatusBadRequest)
+		return
+	case storage.ErrTypeNotAllowed:
+		http.Error(w, "File harus berupa PDF, JPG atau PNG", http.StatusBadRequest)
+		return
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	key = fmt.Sprintf("%s/%d%s", prefix, time.Now().UnixNano(), ext)
+	if err := config.Files.Save(key, file); err != nil {
+		http.Error(w, "Gagal simpan file: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	return key, filepath.Base(header.Filename), mimeType, header.Size, true
+}
+
+// serveStoredFile - Kirim file dari storage ke browser
+func serveStoredFile(w http.ResponseWriter, r *http.Request, key, fileName, mimeType string, modTime time.Time) {
+	f, err := config.Files.Open(key)
+	if err != nil {
+		http.Error(w, "File tidak ditemukan", http.StatusNotFound)
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", mimeType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", fileName))
+	http.ServeContent(w, r, fileName, modTime, f)
+}
+
+// LampiranPage - Daftar lampiran appointment beserta form upload
+func LampiranPage(w http.ResponseWriter, r *http.Request) {
+	sess := middleware.GetSession(r)
+	apt := middleware.GetAppointment(r)
+
+	attachments, err := models.GetAttachmentsByAppointment(config.DB, apt.AppointmentID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]interface{}{
+		"Nama":        sess["Nama"],
+		"UserID":      sess["UserID"],
+		"Appointment": apt,
+		"Attachments": attachments,
+		"BackURL":     dashboardURL(sess["Role"]),
+	}
+
+	tmpl, err := template.ParseFiles("templates/lampiran.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tmpl.Execute(w, data)
+}
+
+// LampiranUploadHandler - Upload lampiran (surat rujukan, hasil sebelumnya, dokumen dokter)
+func LampiranUploadHandler(w http.ResponseWriter, r *http.Request) {
+	sess := middleware.GetSession(r)
+	apt := middleware.GetAppointment(r)
+
+	r.Body = http.MaxBytesReader(w, r.Body, config.MaxUploadSize+1<<20)
+	if err := r.ParseMultipartForm(config.MaxUploadSize); err != nil {
+		http.Error(w, "Ukuran file maksimal 10 MB", http.StatusBadRequest)
+		return
+	}
+
+	key, fileName, mimeType, size, ok := saveUpload(w, r, "file", fmt.Sprintf("lampiran/%d", apt.AppointmentID))
+	if !ok {
+		return
+	}
+
+	keterangan := strings.TrimSpace(r.FormValue("keterangan"))
+	err := models.CreateAttachment(config.DB, apt.AppointmentID, sess["UserID"].(int), key, fileName, mimeType, size, keterangan)
+	if err != nil {
+		config.Files.Delete(key)
+		http.Error(w, "Gagal simpan lampiran: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/appointment/%d/lampiran", apt.AppointmentID), http.StatusSeeOther)
+}
+
+// getLampiran - Ambil lampiran dari URL dan pastikan milik appointment yang sedang diakses
+func getLampiran(w http.ResponseWriter, r *http.Request) (*models.Attachment, bool) {
+	apt := middleware.GetAppointment(r)
+	attachmentID, _ := strconv.Atoi(mux.Vars(r)["aid"])
+
+	attachment, err := models.GetAttachmentByID(config.DB, attachmentID)
+	if err != nil || attachment.AppointmentID != apt.AppointmentID {
+		http.Error(w, "Lampiran tidak ditemukan", http.StatusNotFound)
+		return nil, false
+	}
+
+	return attachment, true
+}
+
+// LampiranDownload - Download/lihat lampiran
+func LampiranDownload(w http.ResponseWriter, r *http.Request) {
+	attachment, ok := getLampiran(w, r)
+	if !ok {
+		return
+	}
+
+	serveStoredFile(w, r, attachment.FileKey, attachment.FileName, attachment.MimeType, attachment.CreatedAt)
+}
+
+// LampiranDeleteHandler - Hapus lampiran (hanya oleh yang mengupload)
+func LampiranDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	sess := middleware.GetSession(r)
+	attachment, ok := getLampiran(w, r)
+	if !ok {
+		return
+	}
+
+	if attachment.UploadedBy != sess["UserID"].(int) {
+		http.Error(w, "Forbidden - Hanya pengupload yang bisa menghapus lampiran", http.StatusForbidden)
+		return
+	}
+
+	if err := models.DeleteAttachment(config.DB, attachment.AttachmentID); err != nil {
+		http.Error(w, "Gagal hapus lampiran: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	config.Files.Delete(attachment.FileKey)
+
+	http.Redirect(w, r, fmt.Sprintf("/appointment/%d/lampiran", attachment.AppointmentID), http.StatusSeeOther)
+}
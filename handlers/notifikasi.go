@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"html/template"
+	"klinik-app/config"
+	"klinik-app/middleware"
+	"klinik-app/models"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// unreadCount - Jumlah notifikasi belum dibaca untuk badge di header dashboard
+func unreadCount(sess map[string]interface{}) int {
+	userID, _ := sess["UserID"].(int)
+	count, err := models.CountUnreadNotifications(config.DB, userID)
+	if err != nil {
+		log.Printf("❌ Hitung notifikasi user %d: %v", userID, err)
+	}
+	return count
+}
+
+// NotifikasiPage - Kotak masuk notifikasi in-app (semua role)
+func NotifikasiPage(w http.ResponseWriter, r *http.Request) {
+	sess := middleware.GetSession(r)
+
+	notifications, err := models.GetNotifications(config.DB, sess["UserID"].(int), 100)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]interface{}{
+		"Nama":          sess["Nama"],
+		"Notifications": notifications,
+		"Unread":        unreadCount(sess),
+		"BackURL":       dashboardURL(sess["Role"]),
+	}
+
+	tmpl, err := template.ParseFiles("templates/notifikasi.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tmpl.Execute(w, data)
+}
+
+// NotifikasiBacaHandler - Tandai notifikasi dibaca lalu buka halaman terkait
+func NotifikasiBacaHandler(w http.ResponseWriter, r *http.Request) {
+	sess := middleware.GetSession(r)
+	notificationID, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	link, err := models.MarkNotificationRead(config.DB, notificationID, sess["UserID"].(int))
+	if err != nil {
+		http.Error(w, "Notifikasi tidak ditemukan", http.StatusNotFound)
+		return
+	}
+
+	// Hanya link internal supaya tidak bisa dipakai untuk redirect ke luar
+	if !strings.HasPrefix(link, "/") || strings.HasPrefix(link, "//") {
+		link = "/notifikasi"
+	}
+	http.Redirect(w, r, link, http.StatusSeeOther)
+}
+
+// NotifikasiBacaSemuaHandler - Tandai semua notifikasi dibaca
+func NotifikasiBacaSemuaHandler(w http.ResponseWriter, r *http.Request) {
+	sess := middleware.GetSession(r)
+
+	if err := models.MarkAllNotificationsRead(config.DB, sess["UserID"].(int)); err != nil {
+		http.Error(w, "Gagal update notifikasi: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/notifikasi", http.StatusSeeOther)
+}
@@ -1,14 +1,17 @@
 package handlers
 
 import (
-	"fmt"
+	"errors"
 	"html/template"
+	"klinik-app/booking"
 	"klinik-app/config"
 	"klinik-app/middleware"
 	"klinik-app/models"
+	"klinik-app/notify"
+	"klinik-app/regno"
 	"net/http"
 	"strconv"
-	"time"
+	"strings"
 )
 
 // PasienDashboard - Dashboard untuk pasien
@@ -24,9 +27,19 @@ func PasienDashboard(w http.ResponseWriter, r *http.Request) {
 
 	data := map[string]interface{}{
 		"Nama":         sess["Nama"],
+		"Unread":       unreadCount(sess),
 		"Appointments": appointments,
 	}
 
+	policy := config.Booking()
+	noShows, err := models.CountNoShows(config.DB, sess["UserID"].(int), policy.NoShowWindowDays, config.Now().Format("2006-01-02"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data["NoShows"] = noShows
+	data["NoShowLimit"] = policy.NoShowLimit
+
 	tmpl, err := template.ParseFiles("templates/pasien_dashboard.html")
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -42,9 +55,27 @@ func PasienCancelAppointment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	sess := middleware.GetSession(r)
 	appointmentID, _ := strconv.Atoi(r.FormValue("appointment_id"))
 
-	err := models.CancelAppointment(config.DB, appointmentID)
+	// Pasien hanya boleh membatalkan appointment miliknya sendiri
+	apt, err := models.GetAppointmentByID(config.DB, appointmentID)
+	if err != nil || apt.PatientID != sess["UserID"].(int) {
+		http.Error(w, "Appointment tidak ditemukan", http.StatusNotFound)
+		return
+	}
+
+	alasan, keterangan, errMsg := cancelReason(r)
+	if errMsg != "" {
+		renderCancelForm(w, apt, "/pasien/cancel-appointment", "/pasien/dashboard", errMsg)
+		return
+	}
+
+	err = models.CancelAppointment(config.DB, appointmentID, sess["UserID"].(int), alasan, keterangan)
+	if err == models.ErrNotCancellable {
+		renderCancelForm(w, apt, "/pasien/cancel-appointment", "/pasien/dashboard", "Appointment ini sudah tidak bisa dibatalkan")
+		return
+	}
 	if err != nil {
 		http.Error(w, "Gagal cancel appointment: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -55,14 +86,50 @@ func PasienCancelAppointment(w http.ResponseWriter, r *http.Request) {
 
 // PasienBookingPage - Tampilkan form booking
 func PasienBookingPage(w http.ResponseWriter, r *http.Request) {
+	renderBookingForm(w, r, "", 0, "")
+}
+
+// renderBookingForm - Form booking beserta pilihan poli, rentang tanggal yang diizinkan dan pesan error (jika ada)
+func renderBookingForm(w http.ResponseWriter, r *http.Request, tanggal string, poliID int, errMsg string) {
 	sess := middleware.GetSession(r)
+	policy := config.Booking()
+	today := config.Now()
+	minDate, maxDate := booking.DateRange(policy, today)
+
+	holidays, err := models.GetUpcomingHolidays(config.DB, minDate)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	polis, err := models.GetActivePolis(config.DB)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]interface{}{
+		"Nama":      sess["Nama"],
+		"Polis":     polis,
+		"PoliID":    poliID,
+		"Tanggal":   tanggal,
+		"Error":     errMsg,
+		"MinDate":   minDate,
+		"MaxDate":   maxDate,
+		"Policy":    policy,
+		"HariTutup": booking.ClosedDaysLabel(policy),
+		"Holidays":  holidays,
+	}
 
 	tmpl, err := template.ParseFiles("templates/pasien_booking.html")
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	tmpl.Execute(w, sess)
+	if errMsg != "" {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	tmpl.Execute(w, data)
 }
 
 // PasienBookingHandler - Proses booking konsultasi
@@ -74,12 +141,43 @@ func PasienBookingHandler(w http.ResponseWriter, r *http.Request) {
 
 	sess := middleware.GetSession(r)
 	tanggal := r.FormValue("tanggal")
+	poliID, _ := strconv.Atoi(r.FormValue("poli_id"))
+
+	// Validasi aturan booking (tanggal, batas booking aktif, kuota harian)
+	err := booking.Validate(config.DB, config.Booking(), sess["UserID"].(int), tanggal, config.Now())
+
+	// Poli wajib dipilih selama ada poli aktif
+	if err == nil {
+		var polis []models.Poli
+		polis, err = models.GetActivePolis(config.DB)
+		if err == nil && len(polis) > 0 {
+			err = booking.CheckPoli(config.DB, poliID, tanggal)
+		}
+	}
+
+	var ruleErr *booking.RuleError
+	if errors.As(err, &ruleErr) {
+		renderBookingForm(w, r, tanggal, poliID, ruleErr.Message)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Gagal validasi booking: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	// Generate nomor registrasi
-	nomorReg := fmt.Sprintf("REG-%d-%s", sess["UserID"], time.Now().Format("20060102150405"))
+	nomorReg, err := newNomorRegistrasi()
+	if err != nil {
+		http.Error(w, "Gagal membuat nomor registrasi: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	// Simpan ke database
-	err := models.CreateAppointment(config.DB, nomorReg, sess["UserID"].(int), tanggal)
+	// Simpan ke database; kuota dicek ulang di dalam transaksi karena bisa saja terisi sejak validasi di atas
+	appointmentID, err := booking.Book(config.DB, config.Booking(), nomorReg, sess["UserID"].(int), poliID, tanggal, config.Now())
+	if errors.As(err, &ruleErr) {
+		renderBookingForm(w, r, tanggal, poliID, ruleErr.Message)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Gagal booking: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -87,8 +185,9 @@ func PasienBookingHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Tampilkan halaman sukses
 	data := map[string]interface{}{
-		"NomorReg": nomorReg,
-		"Tanggal":  tanggal,
+		"AppointmentID": appointmentID,
+		"NomorReg":      nomorReg,
+		"Tanggal":       tanggal,
 	}
 
 	tmpl := `
@@ -101,6 +200,8 @@ func PasienBookingHandler(w http.ResponseWriter, r *http.Request) {
 	<p>Tanggal Konsultasi: <strong>{{.Tanggal}}</strong></p>
 	<p>Status: <strong>Menunggu Persetujuan Admin</strong></p>
 	<br>
+	<a href="/appointment/{{.AppointmentID}}/kalender.ics">📆 Tambahkan ke Kalender</a>
+	<br><br>
 	<a href="/pasien/dashboard">Kembali ke Dashboard</a>
 </body>
 </html>`
@@ -109,6 +210,16 @@ func PasienBookingHandler(w http.ResponseWriter, r *http.Request) {
 	t.Execute(w, data)
 }
 
+// newNomorRegistrasi - Nomor registrasi appointment baru sesuai NOMOR_REG_FORMAT, misal KLN-20261018-00425
+func newNomorRegistrasi() (string, error) {
+	now := config.Now()
+	seq, err := models.NextRegistrationSequence(config.DB, now.Format("2006-01-02"))
+	if err != nil {
+		return "", err
+	}
+	return regno.Format(config.RegistrationFormat(), now, seq), nil
+}
+
 // PasienRiwayat - Tampilkan riwayat konsultasi
 func PasienRiwayat(w http.ResponseWriter, r *http.Request) {
 	sess := middleware.GetSession(r)
@@ -120,9 +231,23 @@ func PasienRiwayat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	labOrders, err := models.GetLabOrdersByPatient(config.DB, sess["UserID"].(int))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	suratSakit, err := models.GetSickLeaveAppointmentIDs(config.DB, sess["UserID"].(int))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	data := map[string]interface{}{
-		"Nama":    sess["Nama"],
-		"History": history,
+		"Nama":       sess["Nama"],
+		"History":    history,
+		"LabOrders":  labOrders,
+		"SuratSakit": suratSakit,
 	}
 
 	tmpl, err := template.ParseFiles("templates/pasien_riwayat.html")
@@ -132,3 +257,81 @@ func PasienRiwayat(w http.ResponseWriter, r *http.Request) {
 	}
 	tmpl.Execute(w, data)
 }
+
+// channelLabels - Nama channel notifikasi yang bisa dipilih pasien
+var channelLabels = map[string]string{
+	"smtp":     "📧 Email",
+	"whatsapp": "💬 WhatsApp",
+	"sms":      "📱 SMS",
+}
+
+// PasienProfilPage - Kontak dan pengaturan notifikasi pasien
+func PasienProfilPage(w http.ResponseWriter, r *http.Request) {
+	sess := middleware.GetSession(r)
+
+	user, err := models.GetUserByID(config.DB, sess["UserID"].(int))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	disabled, err := models.GetDisabledChannels(config.DB, user.UserID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Hanya channel yang aktif di server yang ditampilkan
+	type channelPref struct {
+		Name, Label string
+		Aktif       bool
+	}
+	var channels []channelPref
+	for _, ch := range notify.Channels {
+		if label, ok := channelLabels[ch.Name()]; ok {
+			channels = append(channels, channelPref{ch.Name(), label, !disabled[ch.Name()]})
+		}
+	}
+
+	data := map[string]interface{}{
+		"Nama":     sess["Nama"],
+		"User":     user,
+		"Channels": channels,
+		"Saved":    r.URL.Query().Get("saved") == "1",
+	}
+
+	tmpl, err := template.ParseFiles("templates/pasien_profil.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tmpl.Execute(w, data)
+}
+
+// PasienProfilHandler - Simpan kontak dan pengaturan notifikasi
+func PasienProfilHandler(w http.ResponseWriter, r *http.Request) {
+	sess := middleware.GetSession(r)
+	userID := sess["UserID"].(int)
+
+	email := strings.TrimSpace(r.FormValue("email"))
+	noHP := strings.TrimSpace(r.FormValue("no_hp"))
+
+	if err := models.UpdateUserContact(config.DB, userID, email, noHP); err != nil {
+		http.Error(w, "Gagal simpan kontak: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Sama seperti di halaman: hanya channel yang aktif di server
+	for _, ch := range notify.Channels {
+		if _, ok := channelLabels[ch.Name()]; !ok {
+			continue
+		}
+		aktif := r.FormValue("channel_"+ch.Name()) == "on"
+		if err := models.SetChannelPreference(config.DB, userID, ch.Name(), aktif); err != nil {
+			http.Error(w, "Gagal simpan pengaturan: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	http.Redirect(w, r, "/pasien/profil?saved=1", http.StatusSeeOther)
+}
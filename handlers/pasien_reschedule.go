@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"html/template"
+	"klinik-app/booking"
+	"klinik-app/config"
+	"klinik-app/middleware"
+	"klinik-app/models"
+	"net/http"
+)
+
+// renderRescheduleForm - Form ubah jadwal; jika appointment sudah punya dokter, tampilkan jam kosong dokter tersebut
+func renderRescheduleForm(w http.ResponseWriter, r *http.Request, tanggal, errMsg string) {
+	apt := middleware.GetAppointment(r)
+	policy := config.Booking()
+	now := config.Now()
+	minDate, maxDate := booking.DateRange(policy, now)
+
+	data := map[string]interface{}{
+		"Appointment": apt,
+		"Policy":      policy,
+		"Tanggal":     tanggal,
+		"MinDate":     minDate,
+		"MaxDate":     maxDate,
+		"Error":       errMsg,
+	}
+
+	var ruleErr *booking.RuleError
+	err := booking.CanReschedule(policy, apt, now)
+	if errors.As(err, &ruleErr) {
+		data["Blocked"] = ruleErr.Message
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Pilihan jam langsung hanya untuk appointment approved, di tanggal yang valid
+	if tanggal != "" && apt.Status == "approved" && apt.DoctorID.Valid && data["Blocked"] == nil {
+		err := booking.CheckDate(config.DB, policy, tanggal, now)
+		switch {
+		case errors.As(err, &ruleErr):
+			if errMsg == "" {
+				data["Error"] = ruleErr.Message
+			}
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		default:
+			slots, err := booking.AvailableSlots(config.DB, policy, int(apt.DoctorID.Int64), tanggal, apt.AppointmentID, now)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			data["Slots"] = slots
+		}
+	}
+
+	tmpl, err := template.ParseFiles("templates/pasien_reschedule.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if errMsg != "" {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	tmpl.Execute(w, data)
+}
+
+// PasienReschedulePage - Pasien memilih tanggal (dan jam, jika sudah ada dokter) baru
+func PasienReschedulePage(w http.ResponseWriter, r *http.Request) {
+	renderRescheduleForm(w, r, r.URL.Query().Get("tanggal"), "")
+}
+
+// PasienRescheduleHandler - Simpan perubahan jadwal: ke jam kosong dokter yang sama, atau kembali ke pending
+func PasienRescheduleHandler(w http.ResponseWriter, r *http.Request) {
+	apt := middleware.GetAppointment(r)
+	policy := config.Booking()
+	now := config.Now()
+
+	tanggal := r.FormValue("tanggal")
+	waktu := r.FormValue("waktu")
+
+	err := booking.ValidateReschedule(config.DB, policy, apt, tanggal, now)
+	var ruleErr *booking.RuleError
+	if errors.As(err, &ruleErr) {
+		renderRescheduleForm(w, r, tanggal, ruleErr.Message)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Gagal validasi jadwal: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if waktu != "" {
+		if apt.Status != "approved" || !apt.DoctorID.Valid {
+			renderRescheduleForm(w, r, tanggal, "Pilihan jam hanya tersedia untuk appointment yang sudah disetujui")
+			return
+		}
+
+		var slots []string
+		slots, err = booking.AvailableSlots(config.DB, policy, int(apt.DoctorID.Int64), tanggal, apt.AppointmentID, now)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !contains(slots, waktu) {
+			renderRescheduleForm(w, r, tanggal, models.ErrSlotTaken.Error())
+			return
+		}
+	}
+
+	// Kuota tanggal tujuan diperiksa di dalam transaksi yang mengunci tanggal tersebut
+	err = booking.Reschedule(config.DB, policy, apt, tanggal, waktu)
+	if errors.As(err, &ruleErr) {
+		renderRescheduleForm(w, r, tanggal, ruleErr.Message)
+		return
+	}
+	if err == models.ErrSlotTaken || err == models.ErrNotReschedulable {
+		renderRescheduleForm(w, r, tanggal, err.Error())
+		return
+	}
+	if err != nil {
+		http.Error(w, "Gagal mengubah jadwal: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/pasien/dashboard", http.StatusSeeOther)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"errors"
+	"html/template"
+	"klinik-app/booking"
+	"klinik-app/config"
+	"klinik-app/middleware"
+	"klinik-app/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// PasienWaitlistPage - Daftar tunggu pasien, tawaran slot aktif, dan form daftar
+func PasienWaitlistPage(w http.ResponseWriter, r *http.Request) {
+	sess := middleware.GetSession(r)
+	userID := sess["UserID"].(int)
+
+	entries, err := models.GetPatientWaitlist(config.DB, userID, config.Now().Format("2006-01-02"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	offers, err := models.GetPatientWaitlistOffers(config.DB, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	doctors, err := models.GetDoctors(config.DB)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]interface{}{
+		"Nama":    sess["Nama"],
+		"Entries": entries,
+		"Offers":  offers,
+		"Doctors": doctors,
+		"Tanggal": r.URL.Query().Get("tanggal"),
+		"Today":   config.Now().Format("2006-01-02"),
+	}
+
+	tmpl, err := template.ParseFiles("templates/pasien_waitlist.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tmpl.Execute(w, data)
+}
+
+// PasienWaitlistJoinHandler - Daftar tunggu untuk tanggal (dan dokter tertentu, opsional)
+func PasienWaitlistJoinHandler(w http.ResponseWriter, r *http.Request) {
+	sess := middleware.GetSession(r)
+
+	tanggal := r.FormValue("tanggal")
+	err := booking.CheckDate(config.DB, config.Booking(), tanggal, config.Now())
+	var ruleErr *booking.RuleError
+	if errors.As(err, &ruleErr) {
+		http.Error(w, ruleErr.Message, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	doctorID, _ := strconv.Atoi(r.FormValue("doctor_id"))
+
+	if err := models.JoinWaitlist(config.DB, sess["UserID"].(int), doctorID, tanggal); err != nil {
+		http.Error(w, "Gagal masuk daftar tunggu: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	http.Redirect(w, r, "/pasien/waitlist", http.StatusSeeOther)
+}
+
+// PasienWaitlistLeaveHandler - Keluar dari daftar tunggu
+func PasienWaitlistLeaveHandler(w http.ResponseWriter, r *http.Request) {
+	sess := middleware.GetSession(r)
+	waitlistID, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	if err := models.LeaveWaitlist(config.DB, waitlistID, sess["UserID"].(int)); err != nil {
+		http.Error(w, "Gagal keluar dari daftar tunggu: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/pasien/waitlist", http.StatusSeeOther)
+}
+
+// getWaitlistOffer - Tawaran dari token di URL, hanya untuk pasien yang ditawari
+func getWaitlistOffer(w http.ResponseWriter, r *http.Request) (*models.WaitlistOffer, bool) {
+	sess := middleware.GetSession(r)
+
+	offer, err := models.GetWaitlistOfferByToken(config.DB, mux.Vars(r)["token"])
+	if err != nil || offer.PatientID != sess["UserID"].(int) {
+		http.Error(w, "Tawaran tidak ditemukan", http.StatusNotFound)
+		return nil, false
+	}
+
+	return offer, true
+}
+
+// PasienWaitlistKlaimPage - Konfirmasi klaim slot dari link tawaran
+func PasienWaitlistKlaimPage(w http.ResponseWriter, r *http.Request) {
+	offer, ok := getWaitlistOffer(w, r)
+	if !ok {
+		return
+	}
+
+	data := map[string]interface{}{
+		"Offer":    offer,
+		"Tersedia": offer.Aktif,
+	}
+
+	tmpl, err := template.ParseFiles("templates/waitlist_klaim.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tmpl.Execute(w, data)
+}
+
+// PasienWaitlistKlaimHandler - Klaim slot; appointment langsung approved
+func PasienWaitlistKlaimHandler(w http.ResponseWriter, r *http.Request) {
+	offer, ok := getWaitlistOffer(w, r)
+	if !ok {
+		return
+	}
+
+	nomorReg, err := newNomorRegistrasi()
+	if err != nil {
+		http.Error(w, "Gagal membuat nomor registrasi: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	_, err = booking.Claim(config.DB, config.Booking(), offer, nomorReg, config.Now())
+	if err == models.ErrOfferUnavailable {
+		http.Error(w, "Maaf, tawaran slot ini sudah kedaluwarsa atau slotnya sudah terisi", http.StatusConflict)
+		return
+	}
+	var ruleErr *booking.RuleError
+	if errors.As(err, &ruleErr) {
+		http.Error(w, ruleErr.Message, http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Gagal klaim slot: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/pasien/dashboard", http.StatusSeeOther)
+}
@@ -0,0 +1,97 @@
+// Package ical - Penulis file iCalendar (RFC 5545) sederhana untuk jadwal konsultasi
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type Event struct {
+	UID         string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool // hanya tanggal (jam belum ditentukan)
+	Summary     string
+	Description string
+	Location    string
+	Tentative   bool
+}
+
+// Write - Tulis kalender berisi events; name tampil sebagai nama kalender di aplikasi kalender
+func Write(w io.Writer, name string, events []Event) error {
+	lw := &lineWriter{w: w}
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:-//klinik-app//Jadwal Konsultasi//ID")
+	lw.line("CALSCALE:GREGORIAN")
+	lw.line("METHOD:PUBLISH")
+	lw.line("X-WR-CALNAME:" + escape(name))
+
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+	for _, e := range events {
+		lw.line("BEGIN:VEVENT")
+		lw.line("UID:" + e.UID)
+		lw.line("DTSTAMP:" + stamp)
+		if e.AllDay {
+			lw.line("DTSTART;VALUE=DATE:" + e.Start.Format("20060102"))
+			lw.line("DTEND;VALUE=DATE:" + e.Start.AddDate(0, 0, 1).Format("20060102"))
+		} else {
+			lw.line("DTSTART:" + e.Start.UTC().Format("20060102T150405Z"))
+			lw.line("DTEND:" + e.End.UTC().Format("20060102T150405Z"))
+		}
+		lw.line("SUMMARY:" + escape(e.Summary))
+		if e.Description != "" {
+			lw.line("DESCRIPTION:" + escape(e.Description))
+		}
+		if e.Location != "" {
+			lw.line("LOCATION:" + escape(e.Location))
+		}
+		if e.Tentative {
+			lw.line("STATUS:TENTATIVE")
+		} else {
+			lw.line("STATUS:CONFIRMED")
+		}
+		lw.line("END:VEVENT")
+	}
+
+	lw.line("END:VCALENDAR")
+	return lw.err
+}
+
+// escape - Escape karakter khusus pada nilai TEXT
+func escape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, ";", `\;`)
+	s = strings.ReplaceAll(s, ",", `\,`)
+	s = strings.ReplaceAll(s, "\r\n", `\n`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
+
+type lineWriter struct {
+	w   io.Writer
+	err error
+}
+
+// line - Tulis satu content line dengan CRLF, dilipat tiap 75 byte tanpa memotong karakter UTF-8
+func (lw *lineWriter) line(s string) {
+	if lw.err != nil {
+		return
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range s {
+		size := len(string(r))
+		if n+size > 75 {
+			b.WriteString("\r\n ")
+			n = 1
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	b.WriteString("\r\n")
+
+	_, lw.err = fmt.Fprint(lw.w, b.String())
+}
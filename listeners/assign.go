@@ -0,0 +1,19 @@
+package listeners
+
+import (
+	"context"
+	"klinik-app/assign"
+	"klinik-app/events"
+	"log"
+)
+
+// autoAssign - Appointment baru/dipindah tanggal yang masih pending diberi dokter otomatis (jika AUTO_ASSIGN aktif)
+func autoAssign(ctx context.Context, e events.Event) {
+	if e.Type != events.AppointmentCreated && e.Type != events.AppointmentRescheduled {
+		return
+	}
+
+	if err := assign.AutoAssign(e.AppointmentID); err != nil {
+		log.Printf("❌ Auto-assign for appointment %d: %v", e.AppointmentID, err)
+	}
+}
@@ -0,0 +1,115 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+	"klinik-app/config"
+	"klinik-app/events"
+	"klinik-app/models"
+	"log"
+)
+
+// inboxNotify - Buat notifikasi in-app untuk pihak terkait setiap perubahan appointment
+func inboxNotify(ctx context.Context, e events.Event) {
+	apt, err := models.GetAppointmentByID(config.DB, e.AppointmentID)
+	if err != nil {
+		log.Printf("❌ Inbox %s: appointment %d: %v", e.Type, e.AppointmentID, err)
+		return
+	}
+
+	tanggal := apt.TanggalKonsultasi.Format("02/01/2006")
+	waktu := ""
+	if apt.WaktuKonsultasi.Valid {
+		waktu = " pukul " + apt.WaktuKonsultasi.String
+	}
+
+	var doctorID int
+	if apt.DoctorID.Valid {
+		doctorID = int(apt.DoctorID.Int64)
+	}
+
+	var errs []error
+	add := func(userID int, judul, pesan, link string) {
+		if userID == 0 {
+			return
+		}
+		if err := models.CreateNotification(config.DB, userID, judul, pesan, link); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	switch e.Type {
+	case events.AppointmentCreated:
+		if apt.Status != "pending" {
+			break // appointment yang langsung approved (misal dari waitlist) tidak perlu persetujuan admin
+		}
+		err := models.CreateNotificationForRole(config.DB, "admin", "Booking baru menunggu persetujuan",
+			fmt.Sprintf("%s (%s) booking untuk tanggal %s.", apt.NamaPasien, apt.NomorRegistrasi, tanggal),
+			fmt.Sprintf("/admin/approve/%d", apt.AppointmentID))
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+	case events.AppointmentApproved:
+		add(apt.PatientID, "Jadwal konsultasi disetujui",
+			fmt.Sprintf("Konsultasi %s pada %s%s dengan %s.", apt.NomorRegistrasi, tanggal, waktu, apt.NamaDokter),
+			"/pasien/dashboard")
+		add(doctorID, "Pasien baru ditugaskan",
+			fmt.Sprintf("%s (%s) pada %s%s.", apt.NamaPasien, apt.NomorRegistrasi, tanggal, waktu),
+			"/dokter/dashboard")
+
+	case events.AppointmentRescheduled:
+		if apt.Status == "pending" {
+			// Pasien mengubah tanggal sendiri: perlu dijadwalkan ulang oleh admin
+			err := models.CreateNotificationForRole(config.DB, "admin", "Perubahan jadwal menunggu persetujuan",
+				fmt.Sprintf("%s (%s) mengubah jadwal ke tanggal %s.", apt.NamaPasien, apt.NomorRegistrasi, tanggal),
+				fmt.Sprintf("/admin/approve/%d", apt.AppointmentID))
+			if err != nil {
+				errs = append(errs, err)
+			}
+			break
+		}
+		add(apt.PatientID, "Jadwal konsultasi diubah",
+			fmt.Sprintf("Jadwal baru %s: %s%s dengan %s.", apt.NomorRegistrasi, tanggal, waktu, apt.NamaDokter),
+			"/pasien/dashboard")
+		add(doctorID, "Jadwal pasien diubah",
+			fmt.Sprintf("%s (%s) sekarang pada %s%s.", apt.NamaPasien, apt.NomorRegistrasi, tanggal, waktu),
+			"/dokter/dashboard")
+
+	case events.AppointmentCancelled:
+		alasan := ""
+		if apt.AlasanBatal.Valid {
+			alasan = " Alasan: " + apt.AlasanBatal.String + "."
+		}
+		add(apt.PatientID, "Appointment dibatalkan",
+			fmt.Sprintf("Appointment %s tanggal %s dibatalkan.%s", apt.NomorRegistrasi, tanggal, alasan),
+			"/pasien/riwayat")
+		add(doctorID, "Appointment pasien dibatalkan",
+			fmt.Sprintf("%s (%s) tanggal %s dibatalkan.%s", apt.NamaPasien, apt.NomorRegistrasi, tanggal, alasan),
+			"/dokter/dashboard")
+
+	case events.AppointmentNoShow:
+		add(apt.PatientID, "Tercatat tidak hadir",
+			fmt.Sprintf("Anda tercatat tidak hadir pada konsultasi %s tanggal %s. "+
+				"Batalkan atau ubah jadwal lebih awal jika berhalangan.", apt.NomorRegistrasi, tanggal),
+			"/pasien/dashboard")
+
+	case events.AppointmentCheckedIn:
+		antrian := ""
+		if apt.NomorAntrian.Valid {
+			antrian = fmt.Sprintf(" dengan nomor antrian %03d", apt.NomorAntrian.Int64)
+		}
+		add(doctorID, "Pasien sudah hadir",
+			fmt.Sprintf("%s (%s) sudah check-in%s.", apt.NamaPasien, apt.NomorRegistrasi, antrian),
+			"/dokter/dashboard")
+
+	case events.AppointmentCompleted:
+		add(apt.PatientID, "Hasil konsultasi tersedia",
+			fmt.Sprintf("Hasil konsultasi %s sudah bisa dilihat di riwayat.", apt.NomorRegistrasi),
+			"/pasien/riwayat")
+	}
+
+	for _, err := range errs {
+		log.Printf("❌ Inbox %s: %v", e.Type, err)
+	}
+}
@@ -0,0 +1,15 @@
+// Package listeners - Reaksi terhadap event appointment (notifikasi, dll)
+package listeners
+
+import (
+	"klinik-app/events"
+)
+
+// Register - Daftarkan semua listener ke event bus
+func Register() {
+	events.Subscribe(notifyPatientStatus)
+	events.Subscribe(inboxNotify)
+	events.Subscribe(forwardWebhooks)
+	events.Subscribe(backfillSlot)
+	events.Subscribe(autoAssign)
+}
@@ -0,0 +1,85 @@
+package listeners
+
+import (
+	"context"
+	"klinik-app/config"
+	"klinik-app/events"
+	"klinik-app/models"
+	"klinik-app/notify"
+	"log"
+)
+
+// statusTemplates - Event yang dikirim ke pasien beserta file template pesannya
+var statusTemplates = map[string]string{
+	events.AppointmentApproved:    "templates/notifikasi/appointment_approved.txt",
+	events.AppointmentRescheduled: "templates/notifikasi/appointment_rescheduled.txt",
+	events.AppointmentCancelled:   "templates/notifikasi/appointment_cancelled.txt",
+}
+
+// notifyPatientStatus - Kirim notifikasi ke pasien saat appointment di-approve, dijadwal ulang, atau dibatalkan
+func notifyPatientStatus(ctx context.Context, e events.Event) {
+	file, ok := statusTemplates[e.Type]
+	if !ok {
+		return
+	}
+
+	apt, err := models.GetAppointmentByID(config.DB, e.AppointmentID)
+	if err != nil {
+		log.Printf("❌ Notify %s: appointment %d: %v", e.Type, e.AppointmentID, err)
+		return
+	}
+
+	pasien, err := models.GetUserByID(config.DB, apt.PatientID)
+	if err != nil {
+		log.Printf("❌ Notify %s: patient %d: %v", e.Type, apt.PatientID, err)
+		return
+	}
+
+	disabled, err := models.GetDisabledChannels(config.DB, pasien.UserID)
+	if err != nil {
+		log.Printf("❌ Notify %s: preferences: %v", e.Type, err)
+		return
+	}
+
+	subject, body, err := notify.Render(file, messageData(apt, pasien))
+	if err != nil {
+		log.Printf("❌ Notify %s: template: %v", e.Type, err)
+		return
+	}
+
+	notify.Send(ctx, notify.Message{
+		To: notify.Recipient{
+			UserID: pasien.UserID,
+			Nama:   pasien.Nama,
+			Email:  pasien.Email.String,
+			NoHP:   pasien.NoHP.String,
+		},
+		Subject: subject,
+		Body:    body,
+	}, disabled)
+}
+
+// messageData - Data yang tersedia di template notifikasi
+func messageData(apt *models.Appointment, pasien *models.User) map[string]interface{} {
+	waktu := "-"
+	if start, ok := apt.JadwalMulai(config.Location()); ok {
+		waktu = start.Format("15:04")
+	}
+
+	dokter := apt.NamaDokter
+	if dokter == "" {
+		dokter = "Belum ditentukan"
+	}
+
+	return map[string]interface{}{
+		"Nama":            pasien.Nama,
+		"Klinik":          config.ClinicName(),
+		"NomorRegistrasi": apt.NomorRegistrasi,
+		"Tanggal":         apt.TanggalKonsultasi.Format("02/01/2006"),
+		"Waktu":           waktu,
+		"Dokter":          dokter,
+		"Status":          apt.Status,
+		"AlasanBatal":     apt.AlasanBatal.String,
+		"URL":             config.BaseURL(),
+	}
+}
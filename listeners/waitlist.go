@@ -0,0 +1,19 @@
+package listeners
+
+import (
+	"context"
+	"klinik-app/events"
+	"klinik-app/waitlist"
+	"log"
+)
+
+// backfillSlot - Slot yang dibatalkan ditawarkan ke pasien di daftar tunggu
+func backfillSlot(ctx context.Context, e events.Event) {
+	if e.Type != events.AppointmentCancelled {
+		return
+	}
+
+	if err := waitlist.OfferSlot(ctx, e.AppointmentID); err != nil {
+		log.Printf("❌ Waitlist backfill for appointment %d: %v", e.AppointmentID, err)
+	}
+}
@@ -0,0 +1,98 @@
+package listeners
+
+import (
+	"context"
+	"encoding/json"
+	"klinik-app/config"
+	"klinik-app/events"
+	"klinik-app/models"
+	"klinik-app/webhook"
+	"log"
+	"time"
+)
+
+// webhookPayload - Body JSON yang dikirim ke webhook (tanpa data medis)
+type webhookPayload struct {
+	Event      string             `json:"event"`
+	OccurredAt time.Time          `json:"occurred_at"`
+	Data       webhookAppointment `json:"data"`
+}
+
+type webhookAppointment struct {
+	AppointmentID     int        `json:"appointment_id"`
+	NomorRegistrasi   string     `json:"nomor_registrasi"`
+	PatientID         int        `json:"patient_id"`
+	DoctorID          *int64     `json:"doctor_id"`
+	TanggalKonsultasi string     `json:"tanggal_konsultasi"`
+	WaktuKonsultasi   *string    `json:"waktu_konsultasi"`
+	Status            string     `json:"status"`
+	ArrivedAt         *time.Time `json:"arrived_at"`
+}
+
+// forwardWebhooks - Catat pengiriman untuk setiap webhook aktif yang berlangganan, lalu kirim percobaan pertama
+func forwardWebhooks(ctx context.Context, e events.Event) {
+	webhooks, err := models.GetWebhooks(config.DB)
+	if err != nil {
+		log.Printf("❌ Webhook %s: %v", e.Type, err)
+		return
+	}
+
+	var targets []models.Webhook
+	for _, wh := range webhooks {
+		if wh.Aktif && wh.Subscribed(e.Type) {
+			targets = append(targets, wh)
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	apt, err := models.GetAppointmentByID(config.DB, e.AppointmentID)
+	if err != nil {
+		log.Printf("❌ Webhook %s: appointment %d: %v", e.Type, e.AppointmentID, err)
+		return
+	}
+
+	data := webhookAppointment{
+		AppointmentID:     apt.AppointmentID,
+		NomorRegistrasi:   apt.NomorRegistrasi,
+		PatientID:         apt.PatientID,
+		TanggalKonsultasi: apt.TanggalKonsultasi.Format("2006-01-02"),
+		Status:            apt.Status,
+	}
+	if apt.DoctorID.Valid {
+		data.DoctorID = &apt.DoctorID.Int64
+	}
+	if apt.WaktuKonsultasi.Valid {
+		data.WaktuKonsultasi = &apt.WaktuKonsultasi.String
+	}
+	if apt.ArrivedAt.Valid {
+		data.ArrivedAt = &apt.ArrivedAt.Time
+	}
+
+	payload, err := json.Marshal(webhookPayload{Event: e.Type, OccurredAt: e.At, Data: data})
+	if err != nil {
+		log.Printf("❌ Webhook %s: %v", e.Type, err)
+		return
+	}
+
+	for _, wh := range targets {
+		id, err := models.CreateWebhookDelivery(config.DB, wh.WebhookID, e.Type, string(payload))
+		if err != nil {
+			log.Printf("❌ Webhook %d %s: %v", wh.WebhookID, e.Type, err)
+			continue
+		}
+
+		d := models.WebhookDelivery{
+			DeliveryID: id,
+			WebhookID:  wh.WebhookID,
+			EventType:  e.Type,
+			Payload:    string(payload),
+			URL:        wh.URL,
+			Secret:     wh.Secret,
+		}
+		if err := webhook.Deliver(ctx, d); err != nil {
+			log.Printf("❌ %v", err)
+		}
+	}
+}
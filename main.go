@@ -1,9 +1,14 @@
 package main
 
 import (
+	"context"
+	"klinik-app/booking"
 	"klinik-app/config"
 	"klinik-app/handlers"
+	"klinik-app/listeners"
 	"klinik-app/middleware"
+	"klinik-app/notify"
+	"klinik-app/scheduler"
 	"log"
 	"net/http"
 	"os"
@@ -11,10 +16,35 @@ import (
 	"github.com/gorilla/mux"
 )
 
+// importHolidays - Import kalender hari libur dari HOLIDAYS_FILE (jika diset) saat startup
+func importHolidays() {
+	file := config.Booking().HolidaysFile
+	if file == "" {
+		return
+	}
+
+	n, err := booking.ImportHolidaysFile(config.DB, file)
+	if err != nil {
+		log.Printf("❌ Import holidays from %s failed: %v", file, err)
+		return
+	}
+	log.Printf("✓ %d holidays imported from %s", n, file)
+}
+
 func main() {
 	// Initialize database
 	config.InitDB()
 	defer config.DB.Close()
+	config.Migrate()
+	config.InitStorage()
+	importHolidays()
+	notify.Init()
+	listeners.Register()
+
+	// Background jobs (pengingat jadwal, dll)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scheduler.Start(ctx, scheduler.ReminderJob(), scheduler.WebhookRetryJob(), scheduler.WaitlistJob(), scheduler.NoShowJob())
 
 	// Setup router
 	r := mux.NewRouter()
@@ -25,6 +55,8 @@ func main() {
 	r.HandleFunc("/logout", handlers.LogoutHandler).Methods("GET")
 	r.HandleFunc("/register", handlers.RegisterPage).Methods("GET")
 	r.HandleFunc("/register", handlers.RegisterHandler).Methods("POST")
+	r.HandleFunc("/verifikasi", handlers.VerifikasiPage).Methods("GET")
+	r.HandleFunc("/kalender/{token:[0-9a-f]+}.ics", handlers.KalenderFeed).Methods("GET")
 
 	// Pasien routes (protected)
 	r.HandleFunc("/pasien/dashboard",
@@ -51,12 +83,32 @@ func main() {
 		),
 	).Methods("GET")
 
+	r.HandleFunc("/pasien/cancel/{id}",
+		middleware.RequireAuth(
+			middleware.RequireRole("pasien",
+				middleware.RequireAppointmentAccess(handlers.PasienCancelPage),
+			),
+		),
+	).Methods("GET")
+
 	r.HandleFunc("/pasien/cancel-appointment",
 		middleware.RequireAuth(
 			middleware.RequireRole("pasien", handlers.PasienCancelAppointment),
 		),
 	).Methods("POST")
 
+	r.HandleFunc("/pasien/profil",
+		middleware.RequireAuth(
+			middleware.RequireRole("pasien", handlers.PasienProfilPage),
+		),
+	).Methods("GET")
+
+	r.HandleFunc("/pasien/profil",
+		middleware.RequireAuth(
+			middleware.RequireRole("pasien", handlers.PasienProfilHandler),
+		),
+	).Methods("POST")
+
 	// Admin routes (protected)
 	r.HandleFunc("/admin/dashboard",
 		middleware.RequireAuth(
@@ -88,6 +140,18 @@ func main() {
 		),
 	).Methods("POST")
 
+	r.HandleFunc("/admin/bulk",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminBulkHandler),
+		),
+	).Methods("POST")
+
+	r.HandleFunc("/admin/cancel/{id}",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminCancelPage),
+		),
+	).Methods("GET")
+
 	r.HandleFunc("/admin/cancel-appointment",
 		middleware.RequireAuth(
 			middleware.RequireRole("admin", handlers.AdminCancelAppointment),
@@ -113,6 +177,326 @@ func main() {
 		),
 	).Methods("POST")
 
+	r.HandleFunc("/dokter/konsultasi/{id}/draft",
+		middleware.RequireAuth(
+			middleware.RequireRole("dokter", handlers.DokterDraftHandler),
+		),
+	).Methods("POST")
+
+	r.HandleFunc("/dokter/konsultasi/{id}/amandemen",
+		middleware.RequireAuth(
+			middleware.RequireRole("dokter", handlers.DokterAmandemenPage),
+		),
+	).Methods("GET")
+
+	r.HandleFunc("/dokter/konsultasi/{id}/amandemen",
+		middleware.RequireAuth(
+			middleware.RequireRole("dokter", handlers.DokterAmandemenHandler),
+		),
+	).Methods("POST")
+
+	r.HandleFunc("/dokter/konsultasi/{id}/lab-order",
+		middleware.RequireAuth(
+			middleware.RequireRole("dokter", handlers.DokterLabOrderHandler),
+		),
+	).Methods("POST")
+
+	// Lab routes (protected)
+	r.HandleFunc("/lab/dashboard",
+		middleware.RequireAuth(
+			middleware.RequireRole("lab", handlers.LabDashboard),
+		),
+	).Methods("GET")
+
+	r.HandleFunc("/lab/order/{id}",
+		middleware.RequireAuth(
+			middleware.RequireRole("lab", handlers.LabOrderPage),
+		),
+	).Methods("GET")
+
+	r.HandleFunc("/lab/order/{id}",
+		middleware.RequireAuth(
+			middleware.RequireRole("lab", handlers.LabOrderHandler),
+		),
+	).Methods("POST")
+
+	// Download hasil lab (akses dicek di handler sesuai role)
+	r.HandleFunc("/hasil-lab/{id}/file",
+		middleware.RequireAuth(handlers.LabResultFile),
+	).Methods("GET")
+
+	// Lampiran appointment (pasien pemilik, dokter yang ditugaskan, admin)
+	r.HandleFunc("/appointment/{id}/lampiran",
+		middleware.RequireAuth(
+			middleware.RequireAppointmentAccess(handlers.LampiranPage),
+		),
+	).Methods("GET")
+
+	r.HandleFunc("/appointment/{id}/lampiran",
+		middleware.RequireAuth(
+			middleware.RequireAppointmentAccess(handlers.LampiranUploadHandler),
+		),
+	).Methods("POST")
+
+	r.HandleFunc("/appointment/{id}/lampiran/{aid}",
+		middleware.RequireAuth(
+			middleware.RequireAppointmentAccess(handlers.LampiranDownload),
+		),
+	).Methods("GET")
+
+	r.HandleFunc("/appointment/{id}/lampiran/{aid}/hapus",
+		middleware.RequireAuth(
+			middleware.RequireAppointmentAccess(handlers.LampiranDeleteHandler),
+		),
+	).Methods("POST")
+
+	// Dokumen PDF konsultasi yang sudah selesai
+	r.HandleFunc("/appointment/{id}/dokumen/{jenis}",
+		middleware.RequireAuth(
+			middleware.RequireAppointmentAccess(handlers.DokumenPDF),
+		),
+	).Methods("GET")
+
+	r.HandleFunc("/dokter/konsultasi/{id}/tidak-hadir",
+		middleware.RequireAuth(
+			middleware.RequireRole("dokter", handlers.DokterNoShowHandler),
+		),
+	).Methods("POST")
+
+	r.HandleFunc("/dokter/konsultasi/{id}/surat-sakit",
+		middleware.RequireAuth(
+			middleware.RequireRole("dokter", handlers.DokterSuratSakitHandler),
+		),
+	).Methods("POST")
+
+	// Pembaruan langsung dashboard admin (Server-Sent Events)
+	r.HandleFunc("/admin/events",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminEvents),
+		),
+	).Methods("GET")
+
+	// Kalender: URL feed milik user & download .ics per appointment
+	r.HandleFunc("/kalender",
+		middleware.RequireAuth(handlers.KalenderPage),
+	).Methods("GET")
+
+	r.HandleFunc("/kalender/reset",
+		middleware.RequireAuth(handlers.KalenderResetHandler),
+	).Methods("POST")
+
+	r.HandleFunc("/appointment/{id}/kalender.ics",
+		middleware.RequireAuth(
+			middleware.RequireAppointmentAccess(handlers.AppointmentICS),
+		),
+	).Methods("GET")
+
+	// QR nomor registrasi untuk check-in di loket
+	r.HandleFunc("/appointment/{id}/qr.svg",
+		middleware.RequireAuth(
+			middleware.RequireAppointmentAccess(handlers.AppointmentQR),
+		),
+	).Methods("GET")
+
+	// Pasien ubah jadwal sendiri (sesuai batas kebijakan)
+	r.HandleFunc("/pasien/reschedule/{id}",
+		middleware.RequireAuth(
+			middleware.RequireRole("pasien",
+				middleware.RequireAppointmentAccess(handlers.PasienReschedulePage),
+			),
+		),
+	).Methods("GET")
+
+	r.HandleFunc("/pasien/reschedule/{id}",
+		middleware.RequireAuth(
+			middleware.RequireRole("pasien",
+				middleware.RequireAppointmentAccess(handlers.PasienRescheduleHandler),
+			),
+		),
+	).Methods("POST")
+
+	// Daftar tunggu pasien & klaim slot yang dibatalkan
+	r.HandleFunc("/pasien/waitlist",
+		middleware.RequireAuth(
+			middleware.RequireRole("pasien", handlers.PasienWaitlistPage),
+		),
+	).Methods("GET")
+
+	r.HandleFunc("/pasien/waitlist",
+		middleware.RequireAuth(
+			middleware.RequireRole("pasien", handlers.PasienWaitlistJoinHandler),
+		),
+	).Methods("POST")
+
+	r.HandleFunc("/pasien/waitlist/{id}/batal",
+		middleware.RequireAuth(
+			middleware.RequireRole("pasien", handlers.PasienWaitlistLeaveHandler),
+		),
+	).Methods("POST")
+
+	r.HandleFunc("/pasien/waitlist/klaim/{token}",
+		middleware.RequireAuth(
+			middleware.RequireRole("pasien", handlers.PasienWaitlistKlaimPage),
+		),
+	).Methods("GET")
+
+	r.HandleFunc("/pasien/waitlist/klaim/{token}",
+		middleware.RequireAuth(
+			middleware.RequireRole("pasien", handlers.PasienWaitlistKlaimHandler),
+		),
+	).Methods("POST")
+
+	// Laporan (admin)
+	r.HandleFunc("/admin/laporan",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminLaporanPage),
+		),
+	).Methods("GET")
+
+	// Kalender hari libur (admin)
+	r.HandleFunc("/admin/libur",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminLiburPage),
+		),
+	).Methods("GET")
+
+	r.HandleFunc("/admin/libur",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminLiburHandler),
+		),
+	).Methods("POST")
+
+	r.HandleFunc("/admin/libur/import",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminLiburImportHandler),
+		),
+	).Methods("POST")
+
+	r.HandleFunc("/admin/libur/{tanggal}/hapus",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminLiburDeleteHandler),
+		),
+	).Methods("POST")
+
+	// Pendaftaran pasien datang langsung di loket (admin)
+	r.HandleFunc("/admin/walkin",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminWalkInPage),
+		),
+	).Methods("GET")
+
+	r.HandleFunc("/admin/walkin/pasien",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminWalkInPasienHandler),
+		),
+	).Methods("POST")
+
+	r.HandleFunc("/admin/walkin/{id:[0-9]+}",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminWalkInDaftarPage),
+		),
+	).Methods("GET")
+
+	r.HandleFunc("/admin/walkin/{id:[0-9]+}",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminWalkInDaftarHandler),
+		),
+	).Methods("POST")
+
+	r.HandleFunc("/admin/walkin/tiket/{id:[0-9]+}",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminWalkInTiketPage),
+		),
+	).Methods("GET")
+
+	// Check-in pasien di loket (nomor registrasi / scan QR)
+	r.HandleFunc("/admin/checkin",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminCheckinPage),
+		),
+	).Methods("GET")
+
+	r.HandleFunc("/admin/checkin/{id:[0-9]+}",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminCheckinHandler),
+		),
+	).Methods("POST")
+
+	// Poli, spesialisasi & jadwal praktik dokter (admin)
+	r.HandleFunc("/admin/poli",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminPoliPage),
+		),
+	).Methods("GET")
+
+	r.HandleFunc("/admin/poli",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminPoliHandler),
+		),
+	).Methods("POST")
+
+	r.HandleFunc("/admin/poli/{id}/aktif",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminPoliToggleHandler),
+		),
+	).Methods("POST")
+
+	r.HandleFunc("/admin/dokter/{id}",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminDokterPage),
+		),
+	).Methods("GET")
+
+	r.HandleFunc("/admin/dokter/{id}",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminDokterHandler),
+		),
+	).Methods("POST")
+
+	// Webhook keluar (admin)
+	r.HandleFunc("/admin/webhooks",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminWebhooksPage),
+		),
+	).Methods("GET")
+
+	r.HandleFunc("/admin/webhooks",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminWebhookCreateHandler),
+		),
+	).Methods("POST")
+
+	r.HandleFunc("/admin/webhooks/{id}/aktif",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminWebhookToggleHandler),
+		),
+	).Methods("POST")
+
+	r.HandleFunc("/admin/webhooks/{id}/hapus",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminWebhookDeleteHandler),
+		),
+	).Methods("POST")
+
+	r.HandleFunc("/admin/webhooks/pengiriman/{id}/ulang",
+		middleware.RequireAuth(
+			middleware.RequireRole("admin", handlers.AdminWebhookRetryHandler),
+		),
+	).Methods("POST")
+
+	// Notifikasi in-app (semua role)
+	r.HandleFunc("/notifikasi",
+		middleware.RequireAuth(handlers.NotifikasiPage),
+	).Methods("GET")
+
+	r.HandleFunc("/notifikasi/baca-semua",
+		middleware.RequireAuth(handlers.NotifikasiBacaSemuaHandler),
+	).Methods("POST")
+
+	r.HandleFunc("/notifikasi/{id}/baca",
+		middleware.RequireAuth(handlers.NotifikasiBacaHandler),
+	).Methods("POST")
+
 	// Start server
 	port := os.Getenv("PORT")
 	if port == "" {
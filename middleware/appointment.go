@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"context"
+	"klinik-app/config"
+	"klinik-app/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type contextKey string
+
+const appointmentKey contextKey = "appointment"
+
+// CanAccessAppointment - Aturan akses per appointment: admin semua, dokter yang ditugaskan, pasien pemilik
+func CanAccessAppointment(role string, userID int, apt *models.Appointment) bool {
+	switch role {
+	case "admin":
+		return true
+	case "dokter":
+		return apt.DoctorID.Valid && int(apt.DoctorID.Int64) == userID
+	case "pasien":
+		return apt.PatientID == userID
+	}
+	return false
+}
+
+// RequireAppointmentAccess - Middleware untuk route /.../{id} yang hanya boleh diakses pihak terkait appointment
+func RequireAppointmentAccess(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		appointmentID, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+		apt, err := models.GetAppointmentByID(config.DB, appointmentID)
+		if err != nil {
+			http.Error(w, "Appointment tidak ditemukan", http.StatusNotFound)
+			return
+		}
+
+		session, _ := Store.Get(r, "session-klinik")
+		role, _ := session.Values["role"].(string)
+		userID, _ := session.Values["user_id"].(int)
+
+		if !CanAccessAppointment(role, userID, apt) {
+			http.Error(w, "Forbidden - Anda tidak punya akses", http.StatusForbidden)
+			return
+		}
+
+		next(w, r.WithContext(context.WithValue(r.Context(), appointmentKey, apt)))
+	}
+}
+
+// GetAppointment - Appointment yang sudah dicek oleh RequireAppointmentAccess
+func GetAppointment(r *http.Request) *models.Appointment {
+	apt, _ := r.Context().Value(appointmentKey).(*models.Appointment)
+	return apt
+}
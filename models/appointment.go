@@ -2,7 +2,9 @@ package models
 
 import (
 	"database/sql"
-	"fmt"
+	"errors"
+	"klinik-app/events"
+	"strings"
 	"time"
 )
 
@@ -19,18 +21,136 @@ type Appointment struct {
 	ResepObat         sql.NullString `json:"resep_obat"`
 	CreatedAt         time.Time      `json:"created_at"`
 
+	// Tanda vital
+	TekananDarah sql.NullString  `json:"tekanan_darah"`
+	Suhu         sql.NullFloat64 `json:"suhu"`
+	BeratBadan   sql.NullFloat64 `json:"berat_badan"`
+	Nadi         sql.NullInt64   `json:"nadi"`
+
+	RescheduleCount int `json:"reschedule_count"`
+
+	// Poli tujuan (NULL untuk appointment lama sebelum ada poli)
+	PoliID sql.NullInt64 `json:"poli_id"`
+
+	NomorAntrian sql.NullInt64 `json:"nomor_antrian"`
+	AutoAssigned bool          `json:"auto_assigned"`
+
+	// Waktu pasien check-in di loket
+	ArrivedAt sql.NullTime `json:"arrived_at"`
+
+	// Pembatalan
+	AlasanBatal     sql.NullString `json:"alasan_batal"`
+	KeteranganBatal sql.NullString `json:"keterangan_batal"`
+	CancelledAt     sql.NullTime   `json:"cancelled_at"`
+	DibatalkanOleh  string         `json:"dibatalkan_oleh,omitempty"` // role pembatal: pasien/admin
+
 	// Join fields
 	NamaPasien string `json:"nama_pasien,omitempty"`
 	NamaDokter string `json:"nama_dokter,omitempty"`
+	NamaPoli   string `json:"nama_poli,omitempty"`
 }
 
-// CreateAppointment - Pasien booking konsultasi
-func CreateAppointment(db *sql.DB, nomorReg string, patientID int, tanggal string) error {
-	query := `INSERT INTO appointments (nomor_registrasi, patient_id, tanggal_konsultasi, status) 
-	          VALUES (?, ?, ?, 'pending')`
+// JadwalMulai - Gabungan tanggal & waktu konsultasi di zona waktu loc (false jika waktu belum ditentukan)
+func (a Appointment) JadwalMulai(loc *time.Location) (time.Time, bool) {
+	if !a.WaktuKonsultasi.Valid {
+		return time.Time{}, false
+	}
 
-	_, err := db.Exec(query, nomorReg, patientID, tanggal)
-	return err
+	waktu := strings.TrimSpace(a.WaktuKonsultasi.String)
+	t, err := time.Parse("15:04:05", waktu)
+	if err != nil {
+		t, err = time.Parse("15:04", waktu)
+		if err != nil {
+			return time.Time{}, false
+		}
+	}
+
+	d := a.TanggalKonsultasi
+	return time.Date(d.Year(), d.Month(), d.Day(), t.Hour(), t.Minute(), 0, 0, loc), true
+}
+
+// lockBookingDate - Kunci tanggal lalu baris pasien (urutan tetap untuk semua jalur yang membuat atau memindahkan
+// appointment ke suatu tanggal), kemudian jalankan check di transaksi yang sama. Transaksi lain yang menyentuh
+// tanggal tersebut antre sampai commit, sehingga hitungan kuota di check tidak bisa dilewati.
+func lockBookingDate(tx *sql.Tx, patientID int, tanggal string, check func(tx *sql.Tx) error) error {
+	// Upsert mengunci baris tanggal (exclusive) sampai commit
+	_, err := tx.Exec(`INSERT INTO booking_locks (tanggal) VALUES (?)
+	                   ON DUPLICATE KEY UPDATE tanggal = tanggal`, tanggal)
+	if err != nil {
+		return err
+	}
+
+	var locked int
+	err = tx.QueryRow(`SELECT user_id FROM users WHERE user_id = ? FOR UPDATE`, patientID).Scan(&locked)
+	if err != nil {
+		return err
+	}
+
+	return check(tx)
+}
+
+// CreateAppointment - Pasien booking konsultasi di poli tertentu (poliID 0 = tanpa poli).
+// check (aturan kuota) dijalankan setelah tanggal & pasien dikunci, lihat lockBookingDate.
+func CreateAppointment(db *sql.DB, nomorReg string, patientID, poliID int, tanggal string, check func(tx *sql.Tx) error) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := lockBookingDate(tx, patientID, tanggal, check); err != nil {
+		return 0, err
+	}
+
+	query := `INSERT INTO appointments (nomor_registrasi, patient_id, poli_id, tanggal_konsultasi, status) 
+	          VALUES (?, ?, NULLIF(?, 0), ?, 'pending')`
+
+	res, err := tx.Exec(query, nomorReg, patientID, poliID, tanggal)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	events.Publish(events.AppointmentCreated, int(id))
+	return int(id), nil
+}
+
+// Querier - *sql.DB atau *sql.Tx, supaya hitungan aturan booking bisa dijalankan di dalam transaksi booking
+type Querier interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// CountActiveAppointments - Jumlah appointment pasien yang masih pending/approved
+func CountActiveAppointments(db Querier, patientID int) (int, error) {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM appointments
+	                    WHERE patient_id = ? AND status IN ('pending', 'approved', 'in_progress')`,
+		patientID).Scan(&count)
+	return count, err
+}
+
+// CountAppointmentsOnDate - Jumlah appointment (tidak termasuk yang dibatalkan) di suatu tanggal
+func CountAppointmentsOnDate(db Querier, tanggal string) (int, error) {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM appointments
+	                    WHERE tanggal_konsultasi = ? AND status <> 'cancelled'`, tanggal).Scan(&count)
+	return count, err
+}
+
+// HasAppointmentOnDate - True jika pasien sudah punya appointment aktif di tanggal tersebut
+func HasAppointmentOnDate(db Querier, patientID int, tanggal string) (bool, error) {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM appointments
+	                    WHERE patient_id = ? AND tanggal_konsultasi = ?
+	                      AND status IN ('pending', 'approved', 'in_progress')`, patientID, tanggal).Scan(&count)
+	return count > 0, err
 }
 
 // GetPendingAppointments - Admin melihat pending appointments
@@ -71,30 +191,64 @@ func GetPendingAppointments(db *sql.DB) ([]Appointment, error) {
 	return appointments, nil
 }
 
-// ApproveAppointment - Admin approve dan assign dokter
-func ApproveAppointment(db *sql.DB, appointmentID, doctorID int, waktu string) error {
-	query := `UPDATE appointments 
-	          SET doctor_id = ?, waktu_konsultasi = ?, status = 'approved' 
-	          WHERE appointment_id = ?`
+// ApproveAppointment - Admin approve dan assign dokter untuk appointment pending di tanggal tersebut (YYYY-MM-DD).
+// ErrNotPending jika sudah diproses/dipindah, ErrSlotTaken jika jam dokter sudah terisi.
+func ApproveAppointment(db *sql.DB, appointmentID, doctorID int, tanggal, waktu string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
-	_, err := db.Exec(query, doctorID, waktu, appointmentID)
-	return err
+	if err := approveSlot(tx, appointmentID, doctorID, tanggal, waktu, false); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	events.Publish(events.AppointmentApproved, appointmentID)
+	return nil
 }
 
-// GetTodayAppointmentsByDoctor - Dokter melihat appointment hari ini
-func GetTodayAppointmentsByDoctor(db *sql.DB, doctorID int) ([]Appointment, error) {
-	query := `
-		SELECT 
-			a.appointment_id, a.nomor_registrasi, 
-			a.waktu_konsultasi, u.nama AS nama_pasien
-		FROM appointments a
-		JOIN users u ON a.patient_id = u.user_id
-		WHERE a.doctor_id = ? 
-		  AND DATE(a.tanggal_konsultasi) = CURDATE() 
-		  AND a.status = 'approved'
-	`
+// doctorAgendaQuery - Appointment yang tampil di dashboard dokter (termasuk draft & yang sudah selesai)
+const doctorAgendaQuery = `
+	SELECT 
+		a.appointment_id, a.nomor_registrasi, a.nomor_antrian, a.tanggal_konsultasi,
+		a.waktu_konsultasi, a.status, a.arrived_at, u.nama AS nama_pasien
+	FROM appointments a
+	JOIN users u ON a.patient_id = u.user_id
+	WHERE a.doctor_id = ? 
+	  AND a.status IN ('approved', 'in_progress', 'completed', 'no_show')
+`
+
+// GetDoctorAppointmentsBetween - Appointment dokter dari tanggal dari s/d sampai (YYYY-MM-DD, inklusif).
+// Dalam satu hari, pasien yang sudah check-in tampil lebih dulu sesuai nomor antrian, lalu sisanya menurut jam.
+func GetDoctorAppointmentsBetween(db *sql.DB, doctorID int, dari, sampai string) ([]Appointment, error) {
+	return queryDoctorAgenda(db, doctorAgendaQuery+`
+		  AND a.tanggal_konsultasi BETWEEN ? AND ?
+		ORDER BY a.tanggal_konsultasi ASC, a.arrived_at IS NULL, a.nomor_antrian ASC, a.waktu_konsultasi ASC`,
+		doctorID, dari, sampai)
+}
 
-	rows, err := db.Query(query, doctorID)
+// GetDoctorUpcomingAppointments - Appointment dokter setelah tanggal (YYYY-MM-DD), mulai dari yang terdekat
+func GetDoctorUpcomingAppointments(db *sql.DB, doctorID int, tanggal string, limit int) ([]Appointment, error) {
+	return queryDoctorAgenda(db, doctorAgendaQuery+`
+		  AND a.tanggal_konsultasi > ?
+		ORDER BY a.tanggal_konsultasi ASC, a.waktu_konsultasi ASC
+		LIMIT ?`, doctorID, tanggal, limit)
+}
+
+// GetDoctorPastAppointments - Appointment dokter sebelum tanggal (YYYY-MM-DD), mulai dari yang terbaru
+func GetDoctorPastAppointments(db *sql.DB, doctorID int, tanggal string, limit int) ([]Appointment, error) {
+	return queryDoctorAgenda(db, doctorAgendaQuery+`
+		  AND a.tanggal_konsultasi < ?
+		ORDER BY a.tanggal_konsultasi DESC, a.waktu_konsultasi ASC
+		LIMIT ?`, doctorID, tanggal, limit)
+}
+
+func queryDoctorAgenda(db *sql.DB, query string, args ...interface{}) ([]Appointment, error) {
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -106,7 +260,11 @@ func GetTodayAppointmentsByDoctor(db *sql.DB, doctorID int) ([]Appointment, erro
 		err := rows.Scan(
 			&apt.AppointmentID,
 			&apt.NomorRegistrasi,
+			&apt.NomorAntrian,
+			&apt.TanggalKonsultasi,
 			&apt.WaktuKonsultasi,
+			&apt.Status,
+			&apt.ArrivedAt,
 			&apt.NamaPasien,
 		)
 		if err != nil {
@@ -115,19 +273,87 @@ func GetTodayAppointmentsByDoctor(db *sql.DB, doctorID int) ([]Appointment, erro
 		appointments = append(appointments, apt)
 	}
 
-	return appointments, nil
+	return appointments, rows.Err()
 }
 
-// CompleteConsultation - Dokter input hasil konsultasi
-func CompleteConsultation(db *sql.DB, appointmentID int, gejala, diagnosa, resep string) error {
-	query := `UPDATE appointments 
-	          SET gejala = ?, diagnosa = ?, resep_obat = ?, status = 'completed' 
-	          WHERE appointment_id = ?`
+// HasilKonsultasi - Isian form konsultasi dokter (nilai kosong disimpan sebagai NULL untuk tanda vital)
+type HasilKonsultasi struct {
+	Gejala       string
+	Diagnosa     string
+	Resep        string
+	TekananDarah string
+	Suhu         string
+	BeratBadan   string
+	Nadi         string
+}
+
+// ErrConsultationClosed - Status appointment sudah berubah (misal autosave draft datang setelah konsultasi selesai)
+var ErrConsultationClosed = errors.New("konsultasi ini sudah tidak bisa diubah dari form ini")
+
+// updateConsultation - Simpan isian konsultasi dan ubah status appointment, hanya jika statusnya saat ini
+// salah satu dari from. Baris dikunci dulu supaya autosave & submit yang bersamaan tidak saling menimpa.
+func updateConsultation(tx *sql.Tx, appointmentID int, hasil HasilKonsultasi, status string, from ...string) error {
+	var current string
+	err := tx.QueryRow(`SELECT status FROM appointments WHERE appointment_id = ? FOR UPDATE`, appointmentID).Scan(&current)
+	if err != nil {
+		return err
+	}
+	allowed := false
+	for _, s := range from {
+		allowed = allowed || s == current
+	}
+	if !allowed {
+		return ErrConsultationClosed
+	}
 
-	_, err := db.Exec(query, gejala, diagnosa, resep, appointmentID)
+	query := `UPDATE appointments 
+	          SET gejala = ?, diagnosa = ?, resep_obat = ?,
+	              tekanan_darah = NULLIF(?, ''), suhu = NULLIF(?, ''),
+	              berat_badan = NULLIF(?, ''), nadi = NULLIF(?, ''),
+	              status = ? 
+	          WHERE appointment_id = ? AND status = ?`
+
+	_, err = tx.Exec(query, hasil.Gejala, hasil.Diagnosa, hasil.Resep,
+		hasil.TekananDarah, hasil.Suhu, hasil.BeratBadan, hasil.Nadi, status, appointmentID, current)
 	return err
 }
 
+// SaveConsultationDraft - Dokter simpan catatan sementara (status in_progress)
+func SaveConsultationDraft(db *sql.DB, appointmentID int, hasil HasilKonsultasi) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := updateConsultation(tx, appointmentID, hasil, "in_progress", "approved", "in_progress"); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CompleteConsultation - Dokter input hasil konsultasi, disimpan juga sebagai versi pertama
+func CompleteConsultation(db *sql.DB, appointmentID, doctorID int, hasil HasilKonsultasi) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := updateConsultation(tx, appointmentID, hasil, "completed", "approved", "in_progress"); err != nil {
+		return err
+	}
+	if err := snapshotConsultation(tx, appointmentID, doctorID, ""); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	events.Publish(events.AppointmentCompleted, appointmentID)
+	return nil
+}
+
 // GetPatientActiveAppointments - Pasien melihat appointment aktif (pending & approved)
 func GetPatientActiveAppointments(db *sql.DB, patientID int) ([]Appointment, error) {
 	query := `
@@ -138,7 +364,7 @@ func GetPatientActiveAppointments(db *sql.DB, patientID int) ([]Appointment, err
 		FROM appointments a
 		LEFT JOIN users u ON a.doctor_id = u.user_id
 		WHERE a.patient_id = ? 
-		  AND a.status IN ('pending', 'approved')
+		  AND a.status IN ('pending', 'approved', 'in_progress')
 		ORDER BY a.tanggal_konsultasi ASC
 	`
 
@@ -177,21 +403,71 @@ func GetPatientActiveAppointments(db *sql.DB, patientID int) ([]Appointment, err
 	return appointments, nil
 }
 
-// CancelAppointment - Cancel appointment (update status jadi cancelled)
-func CancelAppointment(db *sql.DB, appointmentID int) error {
-	query := `UPDATE appointments SET status = 'cancelled' WHERE appointment_id = ?`
-	_, err := db.Exec(query, appointmentID)
-	return err
+// ErrNotCancellable - Appointment sudah berjalan/selesai/dibatalkan sehingga tidak bisa dibatalkan lagi
+var ErrNotCancellable = errors.New("appointment tidak bisa dibatalkan")
+
+// CancelAppointment - Cancel appointment beserta alasan, keterangan, siapa dan kapan dibatalkan
+func CancelAppointment(db *sql.DB, appointmentID, cancelledBy int, alasan, keterangan string) error {
+	query := `UPDATE appointments 
+	          SET status = 'cancelled', alasan_batal = ?, keterangan_batal = NULLIF(?, ''),
+	              cancelled_by = ?, cancelled_at = NOW()
+	          WHERE appointment_id = ? AND status IN ('pending', 'approved')`
+	res, err := db.Exec(query, alasan, keterangan, cancelledBy, appointmentID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		if err == nil {
+			err = ErrNotCancellable
+		}
+		return err
+	}
+
+	events.Publish(events.AppointmentCancelled, appointmentID)
+	return nil
 }
 
-// RescheduleAppointment - Admin ubah jadwal appointment
-func RescheduleAppointment(db *sql.DB, appointmentID, doctorID int, tanggal, waktu string) error {
+// RescheduleAppointment - Admin ubah jadwal appointment. check (kuota tanggal tujuan) menerima pasien & tanggal lama
+// dan dijalankan setelah tanggal & pasien dikunci, lihat lockBookingDate.
+func RescheduleAppointment(db *sql.DB, appointmentID, doctorID int, tanggal, waktu string, check func(tx *sql.Tx, patientID int, dari string) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var patientID int
+	var dari time.Time
+	err = tx.QueryRow(`SELECT patient_id, tanggal_konsultasi FROM appointments WHERE appointment_id = ? FOR UPDATE`,
+		appointmentID).Scan(&patientID, &dari)
+	if err != nil {
+		return err
+	}
+	err = lockBookingDate(tx, patientID, tanggal, func(tx *sql.Tx) error {
+		return check(tx, patientID, dari.Format("2006-01-02"))
+	})
+	if err != nil {
+		return err
+	}
+
 	query := `UPDATE appointments 
-	          SET doctor_id = ?, tanggal_konsultasi = ?, waktu_konsultasi = ? 
+	          SET doctor_id = ?, tanggal_konsultasi = ?, waktu_konsultasi = ?, auto_assigned = 0, assigned_at = NOW() 
 	          WHERE appointment_id = ?`
 
-	_, err := db.Exec(query, doctorID, tanggal, waktu, appointmentID)
-	return err
+	_, err = tx.Exec(query, doctorID, tanggal, waktu, appointmentID)
+	if err != nil {
+		return err
+	}
+	if err := resetReminders(tx, appointmentID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	events.Publish(events.AppointmentRescheduled, appointmentID)
+	return nil
 }
 
 // GetAppointmentByID - Get detail appointment
@@ -201,9 +477,16 @@ func GetAppointmentByID(db *sql.DB, appointmentID int) (*Appointment, error) {
 		SELECT 
 			a.appointment_id, a.nomor_registrasi, a.patient_id,
 			a.doctor_id, a.tanggal_konsultasi, a.waktu_konsultasi,
-			a.status, u.nama AS nama_pasien
+			a.status, a.gejala, a.diagnosa, a.resep_obat,
+			a.tekanan_darah, a.suhu, a.berat_badan, a.nadi, a.reschedule_count, a.poli_id, a.nomor_antrian,
+			a.auto_assigned, a.alasan_batal, a.keterangan_batal, a.cancelled_at, a.arrived_at,
+			u.nama AS nama_pasien, COALESCE(ud.nama, '') AS nama_dokter,
+			COALESCE(p.nama, '') AS nama_poli, COALESCE(uc.role, '') AS dibatalkan_oleh
 		FROM appointments a
 		JOIN users u ON a.patient_id = u.user_id
+		LEFT JOIN users ud ON a.doctor_id = ud.user_id
+		LEFT JOIN polyclinics p ON a.poli_id = p.poli_id
+		LEFT JOIN users uc ON a.cancelled_by = uc.user_id
 		WHERE a.appointment_id = ?
 	`
 
@@ -216,7 +499,25 @@ func GetAppointmentByID(db *sql.DB, appointmentID int) (*Appointment, error) {
 		&apt.TanggalKonsultasi,
 		&apt.WaktuKonsultasi,
 		&apt.Status,
+		&apt.Gejala,
+		&apt.Diagnosa,
+		&apt.ResepObat,
+		&apt.TekananDarah,
+		&apt.Suhu,
+		&apt.BeratBadan,
+		&apt.Nadi,
+		&apt.RescheduleCount,
+		&apt.PoliID,
+		&apt.NomorAntrian,
+		&apt.AutoAssigned,
+		&apt.AlasanBatal,
+		&apt.KeteranganBatal,
+		&apt.CancelledAt,
+		&apt.ArrivedAt,
 		&namaPasien,
+		&apt.NamaDokter,
+		&apt.NamaPoli,
+		&apt.DibatalkanOleh,
 	)
 
 	if err != nil {
@@ -228,15 +529,29 @@ func GetAppointmentByID(db *sql.DB, appointmentID int) (*Appointment, error) {
 	return &apt, nil
 }
 
+// IsDoctorOfPatient - Cek apakah dokter pernah/sedang ditugaskan ke pasien tersebut
+func IsDoctorOfPatient(db *sql.DB, doctorID, patientID int) (bool, error) {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM appointments WHERE doctor_id = ? AND patient_id = ?`,
+		doctorID, patientID).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 // GetPatientHistory - Pasien melihat riwayat konsultasi
 func GetPatientHistory(db *sql.DB, patientID int) ([]Appointment, error) {
 	query := `
 		SELECT 
-			a.tanggal_konsultasi, a.status, 
+			a.appointment_id, a.tanggal_konsultasi, a.status, 
 			a.gejala, a.diagnosa, a.resep_obat,
-			u.nama AS nama_dokter
+			a.tekanan_darah, a.suhu, a.berat_badan, a.nadi,
+			a.alasan_batal, a.keterangan_batal, a.cancelled_at,
+			u.nama AS nama_dokter, COALESCE(uc.role, '') AS dibatalkan_oleh
 		FROM appointments a
 		LEFT JOIN users u ON a.doctor_id = u.user_id
+		LEFT JOIN users uc ON a.cancelled_by = uc.user_id
 		WHERE a.patient_id = ?
 		ORDER BY a.tanggal_konsultasi DESC
 	`
@@ -253,12 +568,21 @@ func GetPatientHistory(db *sql.DB, patientID int) ([]Appointment, error) {
 		var namaDokter sql.NullString // ← UBAH: Gunakan sql.NullString untuk handle NULL
 
 		err := rows.Scan(
+			&apt.AppointmentID,
 			&apt.TanggalKonsultasi,
 			&apt.Status,
 			&apt.Gejala,
 			&apt.Diagnosa,
 			&apt.ResepObat,
+			&apt.TekananDarah,
+			&apt.Suhu,
+			&apt.BeratBadan,
+			&apt.Nadi,
+			&apt.AlasanBatal,
+			&apt.KeteranganBatal,
+			&apt.CancelledAt,
 			&namaDokter, // ← Scan ke variable temporary
+			&apt.DibatalkanOleh,
 		)
 		if err != nil {
 			return nil, err
@@ -276,70 +600,3 @@ func GetPatientHistory(db *sql.DB, patientID int) ([]Appointment, error) {
 
 	return history, nil
 }
-
-// GetAllAppointments - Admin melihat SEMUA appointments dengan berbagai status
-// GetAllAppointments - Admin melihat SEMUA appointments dengan berbagai status
-func GetAllAppointments(db *sql.DB) ([]Appointment, error) {
-	query := `
-		SELECT 
-			a.appointment_id, 
-			a.nomor_registrasi, 
-			a.tanggal_konsultasi, 
-			a.waktu_konsultasi,
-			a.status, 
-			a.created_at,
-			up.nama AS nama_pasien,
-			COALESCE(ud.nama, '') AS nama_dokter
-		FROM appointments a
-		JOIN users up ON a.patient_id = up.user_id
-		LEFT JOIN users ud ON a.doctor_id = ud.user_id
-		ORDER BY 
-			CASE a.status
-				WHEN 'pending' THEN 1
-				WHEN 'approved' THEN 2
-				WHEN 'completed' THEN 3
-				WHEN 'cancelled' THEN 4
-			END,
-			a.tanggal_konsultasi ASC
-	`
-
-	rows, err := db.Query(query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var appointments []Appointment
-	for rows.Next() {
-		var apt Appointment
-		var namaDokter sql.NullString
-
-		err := rows.Scan(
-			&apt.AppointmentID,
-			&apt.NomorRegistrasi,
-			&apt.TanggalKonsultasi,
-			&apt.WaktuKonsultasi,
-			&apt.Status,
-			&apt.CreatedAt,
-			&apt.NamaPasien,
-			&namaDokter,
-		)
-		if err != nil {
-			return nil, err
-		}
-
-		// DEBUG - Print ke console
-		fmt.Printf("DEBUG Backend: ID=%d, Status='%s', NamaPasien='%s'\n",
-			apt.AppointmentID, apt.Status, apt.NamaPasien)
-
-		if namaDokter.Valid {
-			apt.NamaDokter = namaDokter.String
-		} else {
-			apt.NamaDokter = "Belum ditentukan"
-		}
-
-		appointments = append(appointments, apt)
-	}
-
-	return appointments, nil
-}
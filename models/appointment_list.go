@@ -0,0 +1,254 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AppointmentFilter - Filter, urutan dan posisi halaman daftar appointment admin
+type AppointmentFilter struct {
+	Status   string // kosong = semua status
+	Dari     string // tanggal konsultasi mulai (YYYY-MM-DD)
+	Sampai   string // tanggal konsultasi sampai (YYYY-MM-DD)
+	DoctorID int
+	Pasien   string // potongan nama atau awalan NIK
+	NomorReg string // awalan nomor registrasi
+	Sort     string // lihat appointmentSorts
+	Cursor   string // posisi halaman dari AppointmentPage.Next
+	Limit    int
+}
+
+// appointmentSort - Kolom kunci urutan (selalu ditambah appointment_id supaya unik) dan arahnya.
+// ByStatus mendahulukan status sesuai statusOrder (pending paling atas) sebelum kolom kunci.
+type appointmentSort struct {
+	Column   string
+	Desc     bool
+	ByStatus bool
+}
+
+// appointmentSorts - Pilihan urutan daftar appointment; "" = pending dulu, lalu tanggal konsultasi terdekat
+var appointmentSorts = map[string]appointmentSort{
+	"":             {"a.tanggal_konsultasi", false, true},
+	"tanggal_desc": {"a.tanggal_konsultasi", true, false},
+	"tanggal_asc":  {"a.tanggal_konsultasi", false, false},
+	"dibuat_desc":  {"a.created_at", true, false},
+	"dibuat_asc":   {"a.created_at", false, false},
+}
+
+// statusOrder - Urutan status pada urutan default (yang perlu ditindaklanjuti admin lebih dulu)
+var statusOrder = []string{"pending", "approved", "in_progress", "completed", "no_show", "cancelled"}
+
+// statusPriority - Nomor urut status (1 = pending); status lain di akhir
+func statusPriority(status string) int {
+	for i, s := range statusOrder {
+		if s == status {
+			return i + 1
+		}
+	}
+	return len(statusOrder) + 1
+}
+
+// statusPriorityColumn - Ekspresi SQL yang sama dengan statusPriority
+func statusPriorityColumn() string {
+	expr := "CASE a.status"
+	for i, s := range statusOrder {
+		expr += " WHEN '" + s + "' THEN " + strconv.Itoa(i+1)
+	}
+	return expr + " ELSE " + strconv.Itoa(len(statusOrder)+1) + " END"
+}
+
+// ErrInvalidCursor - Cursor halaman rusak atau tidak cocok dengan urutan yang dipilih
+var ErrInvalidCursor = errors.New("posisi halaman tidak valid")
+
+// AppointmentPage - Satu halaman hasil; Next kosong jika sudah halaman terakhir
+type AppointmentPage struct {
+	Appointments []Appointment
+	Next         string
+}
+
+// encodeCursor - Cursor = prioritas status (0 jika urutan tidak per status) + nilai kunci urutan + appointment_id
+// baris terakhir
+func encodeCursor(prio int, key time.Time, id int) string {
+	raw := strconv.Itoa(prio) + "|" + key.Format(time.RFC3339) + "|" + strconv.Itoa(id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (int, time.Time, int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, time.Time{}, 0, ErrInvalidCursor
+	}
+	parts := strings.SplitN(string(raw), "|", 3)
+	if len(parts) != 3 {
+		return 0, time.Time{}, 0, ErrInvalidCursor
+	}
+	prio, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, time.Time{}, 0, ErrInvalidCursor
+	}
+	key, err := time.Parse(time.RFC3339, parts[1])
+	if err != nil {
+		return 0, time.Time{}, 0, ErrInvalidCursor
+	}
+	id, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return 0, time.Time{}, 0, ErrInvalidCursor
+	}
+	return prio, key, id, nil
+}
+
+// GetAppointmentsPage - Daftar appointment admin dengan filter dan keyset pagination
+// (tidak memakai OFFSET, sehingga tetap cepat untuk puluhan ribu baris)
+func GetAppointmentsPage(db *sql.DB, f AppointmentFilter) (*AppointmentPage, error) {
+	sort, ok := appointmentSorts[f.Sort]
+	if !ok {
+		sort = appointmentSorts[""]
+	}
+	if f.Limit <= 0 || f.Limit > 200 {
+		f.Limit = 50
+	}
+
+	var where []string
+	var args []interface{}
+	if f.Status != "" {
+		where = append(where, "a.status = ?")
+		args = append(args, f.Status)
+	}
+	if f.Dari != "" {
+		where = append(where, "a.tanggal_konsultasi >= ?")
+		args = append(args, f.Dari)
+	}
+	if f.Sampai != "" {
+		where = append(where, "a.tanggal_konsultasi <= ?")
+		args = append(args, f.Sampai)
+	}
+	if f.DoctorID > 0 {
+		where = append(where, "a.doctor_id = ?")
+		args = append(args, f.DoctorID)
+	}
+	if f.Pasien != "" {
+		where = append(where, "(up.nama LIKE CONCAT('%', ?, '%') OR up.nik LIKE CONCAT(?, '%'))")
+		args = append(args, f.Pasien, f.Pasien)
+	}
+	if f.NomorReg != "" {
+		where = append(where, "a.nomor_registrasi LIKE CONCAT(?, '%')")
+		args = append(args, f.NomorReg)
+	}
+
+	// Keyset: lanjut setelah baris terakhir halaman sebelumnya
+	op, dir := ">", "ASC"
+	if sort.Desc {
+		op, dir = "<", "DESC"
+	}
+	if f.Cursor != "" {
+		prio, key, id, err := decodeCursor(f.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		after := "(" + sort.Column + " " + op + " ? OR (" + sort.Column + " = ? AND a.appointment_id " + op + " ?))"
+		if sort.ByStatus {
+			// Prioritas status selalu naik; di dalam status yang sama mengikuti arah kolom kunci
+			after = "(" + statusPriorityColumn() + " > ? OR (" + statusPriorityColumn() + " = ? AND " + after + "))"
+			args = append(args, prio, prio)
+		}
+		where = append(where, after)
+		args = append(args, key, key, id)
+	}
+
+	query := `
+		SELECT 
+			a.appointment_id, 
+			a.nomor_registrasi, 
+			a.tanggal_konsultasi, 
+			a.waktu_konsultasi,
+			a.status, 
+			a.created_at,
+			a.auto_assigned,
+			a.alasan_batal,
+			a.keterangan_batal,
+			a.cancelled_at,
+			a.arrived_at,
+			up.nama AS nama_pasien,
+			COALESCE(ud.nama, '') AS nama_dokter,
+			COALESCE(p.nama, '') AS nama_poli,
+			COALESCE(uc.role, '') AS dibatalkan_oleh
+		FROM appointments a
+		JOIN users up ON a.patient_id = up.user_id
+		LEFT JOIN users ud ON a.doctor_id = ud.user_id
+		LEFT JOIN polyclinics p ON a.poli_id = p.poli_id
+		LEFT JOIN users uc ON a.cancelled_by = uc.user_id`
+	if len(where) > 0 {
+		query += "\n\t\tWHERE " + strings.Join(where, " AND ")
+	}
+	orderBy := sort.Column + " " + dir + ", a.appointment_id " + dir
+	if sort.ByStatus {
+		orderBy = statusPriorityColumn() + " ASC, " + orderBy
+	}
+	query += "\n\t\tORDER BY " + orderBy + "\n\t\tLIMIT ?"
+	args = append(args, f.Limit+1)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	page := &AppointmentPage{}
+	for rows.Next() {
+		var apt Appointment
+		var namaDokter sql.NullString
+
+		err := rows.Scan(
+			&apt.AppointmentID,
+			&apt.NomorRegistrasi,
+			&apt.TanggalKonsultasi,
+			&apt.WaktuKonsultasi,
+			&apt.Status,
+			&apt.CreatedAt,
+			&apt.AutoAssigned,
+			&apt.AlasanBatal,
+			&apt.KeteranganBatal,
+			&apt.CancelledAt,
+			&apt.ArrivedAt,
+			&apt.NamaPasien,
+			&namaDokter,
+			&apt.NamaPoli,
+			&apt.DibatalkanOleh,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if namaDokter.Valid {
+			apt.NamaDokter = namaDokter.String
+		} else {
+			apt.NamaDokter = "Belum ditentukan"
+		}
+
+		page.Appointments = append(page.Appointments, apt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Baris ekstra hanya penanda masih ada halaman berikutnya
+	if len(page.Appointments) > f.Limit {
+		page.Appointments = page.Appointments[:f.Limit]
+		last := page.Appointments[f.Limit-1]
+		key := last.TanggalKonsultasi
+		if sort.Column == "a.created_at" {
+			key = last.CreatedAt
+		}
+		prio := 0
+		if sort.ByStatus {
+			prio = statusPriority(last.Status)
+		}
+		page.Next = encodeCursor(prio, key, last.AppointmentID)
+	}
+
+	return page, nil
+}
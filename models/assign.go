@@ -0,0 +1,94 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"klinik-app/events"
+	"time"
+)
+
+// ErrNotPending - Appointment sudah diproses atau jadwalnya berubah sejak form/antrian approve dibaca
+var ErrNotPending = errors.New("appointment sudah diproses atau jadwalnya berubah, silakan muat ulang halaman")
+
+// approveSlot - Approve appointment pending di tanggal tersebut ke dokter & jam waktu di dalam transaksi.
+// Baris appointment dikunci lebih dulu (ErrNotPending jika sudah bukan pending di tanggal itu), lalu appointment
+// dokter pada jam tersebut dikunci dan dihitung (ErrSlotTaken jika sudah terisi).
+func approveSlot(tx *sql.Tx, appointmentID, doctorID int, tanggal, waktu string, autoAssigned bool) error {
+	var status string
+	var tgl time.Time
+	err := tx.QueryRow(`SELECT status, tanggal_konsultasi FROM appointments WHERE appointment_id = ? FOR UPDATE`,
+		appointmentID).Scan(&status, &tgl)
+	if err != nil {
+		return err
+	}
+	if status != "pending" || tgl.Format("2006-01-02") != tanggal {
+		return ErrNotPending
+	}
+
+	var count int
+	err = tx.QueryRow(`SELECT COUNT(*) FROM appointments
+	                   WHERE doctor_id = ? AND tanggal_konsultasi = ? AND waktu_konsultasi = ?
+	                     AND status IN ('approved', 'in_progress', 'completed') AND appointment_id <> ?
+	                   FOR UPDATE`, doctorID, tanggal, waktu, appointmentID).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrSlotTaken
+	}
+
+	_, err = tx.Exec(`UPDATE appointments
+	                  SET doctor_id = ?, waktu_konsultasi = ?, status = 'approved', auto_assigned = ?, assigned_at = NOW()
+	                  WHERE appointment_id = ? AND status = 'pending'`,
+		doctorID, waktu, autoAssigned, appointmentID)
+	return err
+}
+
+// AutoApproveAppointment - Approve otomatis; hanya berhasil jika appointment masih pending di tanggal tersebut
+// (false jika sudah diproses admin).
+// Jam dicek ulang dengan mengunci appointment dokter pada tanggal tersebut; ErrSlotTaken jika sudah terisi.
+func AutoApproveAppointment(db *sql.DB, appointmentID, doctorID int, tanggal, waktu string) (bool, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	err = approveSlot(tx, appointmentID, doctorID, tanggal, waktu, true)
+	if err == ErrNotPending {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	events.Publish(events.AppointmentApproved, appointmentID)
+	return true, nil
+}
+
+// LastAssignedDoctor - Dokter yang terakhir ditetapkan untuk appointment di poli tersebut (0 jika belum ada)
+func LastAssignedDoctor(db *sql.DB, poliID int) (int, error) {
+	var doctorID int
+	err := db.QueryRow(`SELECT doctor_id FROM appointments
+	                    WHERE poli_id = ? AND doctor_id IS NOT NULL AND assigned_at IS NOT NULL
+	                    ORDER BY assigned_at DESC, appointment_id DESC LIMIT 1`, poliID).Scan(&doctorID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return doctorID, err
+}
+
+// PreviousDoctor - Dokter konsultasi terakhir pasien yang sudah selesai (0 jika belum pernah)
+func PreviousDoctor(db *sql.DB, patientID int) (int, error) {
+	var doctorID int
+	err := db.QueryRow(`SELECT doctor_id FROM appointments
+	                    WHERE patient_id = ? AND status = 'completed' AND doctor_id IS NOT NULL
+	                    ORDER BY tanggal_konsultasi DESC, appointment_id DESC LIMIT 1`, patientID).Scan(&doctorID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return doctorID, err
+}
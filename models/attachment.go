@@ -0,0 +1,115 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Attachment struct {
+	AttachmentID  int            `json:"attachment_id"`
+	AppointmentID int            `json:"appointment_id"`
+	UploadedBy    int            `json:"uploaded_by"`
+	FileKey       string         `json:"-"`
+	FileName      string         `json:"file_name"`
+	MimeType      string         `json:"mime_type"`
+	Size          int64          `json:"size"`
+	Keterangan    sql.NullString `json:"keterangan"`
+	CreatedAt     time.Time      `json:"created_at"`
+
+	// Join fields
+	NamaUploader string `json:"nama_uploader,omitempty"`
+	RoleUploader string `json:"role_uploader,omitempty"`
+}
+
+// SizeKB - Ukuran file dalam KB untuk ditampilkan
+func (a Attachment) SizeKB() int64 {
+	return (a.Size + 1023) / 1024
+}
+
+// CreateAttachment - Simpan metadata lampiran (file sudah disimpan di storage)
+func CreateAttachment(db *sql.DB, appointmentID, uploadedBy int, fileKey, fileName, mimeType string, size int64, keterangan string) error {
+	query := `INSERT INTO attachments (appointment_id, uploaded_by, file_key, file_name, mime_type, size, keterangan)
+	          VALUES (?, ?, ?, ?, ?, ?, NULLIF(?, ''))`
+
+	_, err := db.Exec(query, appointmentID, uploadedBy, fileKey, fileName, mimeType, size, keterangan)
+	return err
+}
+
+// GetAttachmentsByAppointment - Semua lampiran pada satu appointment
+func GetAttachmentsByAppointment(db *sql.DB, appointmentID int) ([]Attachment, error) {
+	query := `
+		SELECT
+			t.attachment_id, t.appointment_id, t.uploaded_by,
+			t.file_key, t.file_name, t.mime_type, t.size,
+			t.keterangan, t.created_at,
+			u.nama AS nama_uploader, u.role AS role_uploader
+		FROM attachments t
+		JOIN users u ON t.uploaded_by = u.user_id
+		WHERE t.appointment_id = ?
+		ORDER BY t.created_at ASC
+	`
+
+	rows, err := db.Query(query, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []Attachment
+	for rows.Next() {
+		var a Attachment
+		err := rows.Scan(
+			&a.AttachmentID,
+			&a.AppointmentID,
+			&a.UploadedBy,
+			&a.FileKey,
+			&a.FileName,
+			&a.MimeType,
+			&a.Size,
+			&a.Keterangan,
+			&a.CreatedAt,
+			&a.NamaUploader,
+			&a.RoleUploader,
+		)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, a)
+	}
+
+	return attachments, nil
+}
+
+// GetAttachmentByID - Detail satu lampiran
+func GetAttachmentByID(db *sql.DB, attachmentID int) (*Attachment, error) {
+	var a Attachment
+	query := `
+		SELECT attachment_id, appointment_id, uploaded_by,
+		       file_key, file_name, mime_type, size, keterangan, created_at
+		FROM attachments
+		WHERE attachment_id = ?
+	`
+
+	err := db.QueryRow(query, attachmentID).Scan(
+		&a.AttachmentID,
+		&a.AppointmentID,
+		&a.UploadedBy,
+		&a.FileKey,
+		&a.FileName,
+		&a.MimeType,
+		&a.Size,
+		&a.Keterangan,
+		&a.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+// DeleteAttachment - Hapus metadata lampiran
+func DeleteAttachment(db *sql.DB, attachmentID int) error {
+	_, err := db.Exec(`DELETE FROM attachments WHERE attachment_id = ?`, attachmentID)
+	return err
+}
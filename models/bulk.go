@@ -0,0 +1,234 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"klinik-app/events"
+	"time"
+)
+
+// BulkResult - Hasil aksi massal untuk satu appointment
+type BulkResult struct {
+	AppointmentID   int
+	NomorRegistrasi string
+	NamaPasien      string
+	OK              bool
+	Pesan           string
+}
+
+// bulkRow - Data appointment yang dikunci selama aksi massal
+type bulkRow struct {
+	AppointmentID int
+	PatientID     int
+	Status        string
+	Tanggal       time.Time
+	PoliID        sql.NullInt64
+}
+
+// runBulk - Jalankan apply untuk setiap appointment dalam satu transaksi. apply mengembalikan pesan dan
+// ok=false jika baris dilewati (aturan tidak terpenuhi); error database membatalkan seluruh transaksi.
+func runBulk(db *sql.DB, ids []int, event string, apply func(tx *sql.Tx, row bulkRow) (string, bool, error)) ([]BulkResult, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var results []BulkResult
+	for _, id := range ids {
+		var row bulkRow
+		res := BulkResult{AppointmentID: id}
+		err := tx.QueryRow(`SELECT a.appointment_id, a.patient_id, a.nomor_registrasi, a.status, a.tanggal_konsultasi, a.poli_id, u.nama
+		                    FROM appointments a
+		                    JOIN users u ON a.patient_id = u.user_id
+		                    WHERE a.appointment_id = ?
+		                    FOR UPDATE`, id).
+			Scan(&row.AppointmentID, &row.PatientID, &res.NomorRegistrasi, &row.Status, &row.Tanggal, &row.PoliID, &res.NamaPasien)
+		if err == sql.ErrNoRows {
+			res.Pesan = "Appointment tidak ditemukan"
+			results = append(results, res)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		res.Pesan, res.OK, err = apply(tx, row)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, res)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	for _, res := range results {
+		if res.OK {
+			events.Publish(event, res.AppointmentID)
+		}
+	}
+	return results, nil
+}
+
+// BulkApprove - Approve appointment pending sekaligus ke satu dokter. Tiap appointment mendapat jam kosong
+// pertama dari slots (HH:MM, urut) yang tidak lebih awal dari mulai, di dalam jam praktik dokter dan belum lewat
+// pada now. Aturan dokter sama dengan approve satuan: jika ada dokter poli appointment yang praktik pada
+// tanggal tersebut, dokter harus salah satunya.
+func BulkApprove(db *sql.DB, ids []int, doctorID int, mulai string, slots []string, now time.Time) ([]BulkResult, error) {
+	// Jam yang sudah terisi per tanggal, termasuk yang baru diberikan dalam transaksi ini
+	booked := map[string]map[string]bool{}
+	// Dokter yang praktik per poli & tanggal
+	onDuty := map[string][]DoctorOnDuty{}
+	today, jamSekarang := now.Format("2006-01-02"), now.Format("15:04")
+
+	return runBulk(db, ids, events.AppointmentApproved, func(tx *sql.Tx, row bulkRow) (string, bool, error) {
+		if row.Status != "pending" {
+			return "Dilewati: status " + row.Status, false, nil
+		}
+
+		tanggal := row.Tanggal.Format("2006-01-02")
+		if tanggal < today {
+			return "Dilewati: tanggal " + row.Tanggal.Format("02/01/2006") + " sudah lewat", false, nil
+		}
+
+		// Batas jam dari jadwal praktik dokter (kosong = poli tanpa jadwal, tidak dibatasi)
+		var jamMulai, jamSelesai string
+		if row.PoliID.Valid {
+			key := fmt.Sprintf("%d/%s", row.PoliID.Int64, tanggal)
+			if _, ok := onDuty[key]; !ok {
+				doctors, err := GetDoctorsOnDuty(db, int(row.PoliID.Int64), tanggal)
+				if err != nil {
+					return "", false, err
+				}
+				onDuty[key] = doctors
+			}
+
+			if len(onDuty[key]) > 0 {
+				found := false
+				for _, d := range onDuty[key] {
+					if d.UserID == doctorID {
+						found, jamMulai, jamSelesai = true, d.JamMulai, d.JamSelesai
+					}
+				}
+				if !found {
+					return "Dilewati: dokter tidak praktik di poli appointment ini pada " + row.Tanggal.Format("02/01/2006"), false, nil
+				}
+			}
+		}
+
+		if booked[tanggal] == nil {
+			rows, err := tx.Query(`SELECT TIME_FORMAT(waktu_konsultasi, '%H:%i') FROM appointments
+			                       WHERE doctor_id = ? AND tanggal_konsultasi = ? AND waktu_konsultasi IS NOT NULL
+			                         AND status IN ('approved', 'in_progress', 'completed')
+			                       FOR UPDATE`, doctorID, tanggal)
+			if err != nil {
+				return "", false, err
+			}
+			booked[tanggal] = map[string]bool{}
+			for rows.Next() {
+				var waktu string
+				if err := rows.Scan(&waktu); err != nil {
+					rows.Close()
+					return "", false, err
+				}
+				booked[tanggal][waktu] = true
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return "", false, err
+			}
+		}
+
+		waktu := ""
+		for _, s := range slots {
+			if s < mulai || booked[tanggal][s] {
+				continue
+			}
+			if jamMulai != "" && (s < jamMulai || s >= jamSelesai) {
+				continue
+			}
+			if tanggal == today && s <= jamSekarang {
+				continue
+			}
+			waktu = s
+			break
+		}
+		if waktu == "" {
+			pesan := "Dilewati: tidak ada jam kosong untuk dokter pada " + row.Tanggal.Format("02/01/2006")
+			if jamMulai != "" {
+				pesan += " (praktik " + jamMulai + "–" + jamSelesai + ")"
+			}
+			return pesan, false, nil
+		}
+
+		_, err := tx.Exec(`UPDATE appointments
+		                   SET doctor_id = ?, waktu_konsultasi = ?, status = 'approved', auto_assigned = 0, assigned_at = NOW()
+		                   WHERE appointment_id = ?`, doctorID, waktu, row.AppointmentID)
+		if err != nil {
+			return "", false, err
+		}
+		booked[tanggal][waktu] = true
+		return "Approved, " + row.Tanggal.Format("02/01/2006") + " pukul " + waktu, true, nil
+	})
+}
+
+// BulkCancel - Batalkan appointment pending/approved sekaligus dengan satu alasan
+func BulkCancel(db *sql.DB, ids []int, cancelledBy int, alasan, keterangan string) ([]BulkResult, error) {
+	return runBulk(db, ids, events.AppointmentCancelled, func(tx *sql.Tx, row bulkRow) (string, bool, error) {
+		if row.Status != "pending" && row.Status != "approved" {
+			return "Dilewati: status " + row.Status, false, nil
+		}
+
+		_, err := tx.Exec(`UPDATE appointments
+		                   SET status = 'cancelled', alasan_batal = ?, keterangan_batal = NULLIF(?, ''),
+		                       cancelled_by = ?, cancelled_at = NOW()
+		                   WHERE appointment_id = ?`, alasan, keterangan, cancelledBy, row.AppointmentID)
+		if err != nil {
+			return "", false, err
+		}
+		return "Dibatalkan", true, nil
+	})
+}
+
+// BulkReschedule - Pindahkan appointment pending/approved ke tanggal lain; dokter & jam dilepas dan appointment
+// kembali pending untuk di-approve ulang. check dijalankan per appointment setelah tanggal & pasien dikunci
+// (lihat lockBookingDate) dengan tanggal lama (YYYY-MM-DD); pesan tidak kosong berarti baris dilewati.
+func BulkReschedule(db *sql.DB, ids []int, tanggal string, check func(tx *sql.Tx, patientID int, dari string) (string, error)) ([]BulkResult, error) {
+	baru, err := time.Parse("2006-01-02", tanggal)
+	if err != nil {
+		return nil, err
+	}
+
+	return runBulk(db, ids, events.AppointmentRescheduled, func(tx *sql.Tx, row bulkRow) (string, bool, error) {
+		if row.Status != "pending" && row.Status != "approved" {
+			return "Dilewati: status " + row.Status, false, nil
+		}
+
+		var pesan string
+		err := lockBookingDate(tx, row.PatientID, tanggal, func(tx *sql.Tx) error {
+			var err error
+			pesan, err = check(tx, row.PatientID, row.Tanggal.Format("2006-01-02"))
+			return err
+		})
+		if err != nil {
+			return "", false, err
+		}
+		if pesan != "" {
+			return "Dilewati: " + pesan, false, nil
+		}
+
+		_, err = tx.Exec(`UPDATE appointments
+		                  SET tanggal_konsultasi = ?, doctor_id = NULL, waktu_konsultasi = NULL,
+		                      status = 'pending', auto_assigned = 0
+		                  WHERE appointment_id = ?`, tanggal, row.AppointmentID)
+		if err != nil {
+			return "", false, err
+		}
+		if err := resetReminders(tx, row.AppointmentID); err != nil {
+			return "", false, err
+		}
+		return "Dipindah ke " + baru.Format("02/01/2006") + ", menunggu approval ulang", true, nil
+	})
+}
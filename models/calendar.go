@@ -0,0 +1,109 @@
+package models
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+)
+
+func newCalendarToken() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// GetOrCreateCalendarToken - Token feed kalender user; dibuat saat pertama kali diminta
+func GetOrCreateCalendarToken(db *sql.DB, userID int) (string, error) {
+	var token sql.NullString
+	err := db.QueryRow(`SELECT calendar_token FROM users WHERE user_id = ?`, userID).Scan(&token)
+	if err != nil {
+		return "", err
+	}
+	if token.Valid {
+		return token.String, nil
+	}
+
+	return ResetCalendarToken(db, userID)
+}
+
+// ResetCalendarToken - Ganti token feed kalender (URL lama otomatis tidak berlaku)
+func ResetCalendarToken(db *sql.DB, userID int) (string, error) {
+	token, err := newCalendarToken()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = db.Exec(`UPDATE users SET calendar_token = ? WHERE user_id = ?`, token, userID)
+	return token, err
+}
+
+// GetUserByCalendarToken - Pemilik feed kalender dari token di URL
+func GetUserByCalendarToken(db *sql.DB, token string) (*User, error) {
+	var user User
+	err := db.QueryRow(`SELECT user_id, nama, role FROM users WHERE calendar_token = ?`, token).Scan(
+		&user.UserID,
+		&user.Nama,
+		&user.Role,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// GetCalendarAppointments - Jadwal untuk feed kalender: dokter semua yang approved,
+// pasien miliknya sendiri yang pending/approved (30 hari sebelum today ke depan)
+func GetCalendarAppointments(db *sql.DB, userID int, role, today string) ([]Appointment, error) {
+	var filter string
+	switch role {
+	case "dokter":
+		filter = `a.doctor_id = ? AND a.status = 'approved'`
+	case "pasien":
+		filter = `a.patient_id = ? AND a.status IN ('pending', 'approved')`
+	default:
+		return nil, nil
+	}
+
+	query := `
+		SELECT
+			a.appointment_id, a.nomor_registrasi, a.patient_id,
+			a.tanggal_konsultasi, a.waktu_konsultasi, a.status,
+			up.nama AS nama_pasien, COALESCE(ud.nama, '') AS nama_dokter
+		FROM appointments a
+		JOIN users up ON a.patient_id = up.user_id
+		LEFT JOIN users ud ON a.doctor_id = ud.user_id
+		WHERE ` + filter + `
+		  AND a.tanggal_konsultasi >= ? - INTERVAL 30 DAY
+		ORDER BY a.tanggal_konsultasi ASC, a.waktu_konsultasi ASC
+	`
+
+	rows, err := db.Query(query, userID, today)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var appointments []Appointment
+	for rows.Next() {
+		var apt Appointment
+		err := rows.Scan(
+			&apt.AppointmentID,
+			&apt.NomorRegistrasi,
+			&apt.PatientID,
+			&apt.TanggalKonsultasi,
+			&apt.WaktuKonsultasi,
+			&apt.Status,
+			&apt.NamaPasien,
+			&apt.NamaDokter,
+		)
+		if err != nil {
+			return nil, err
+		}
+		appointments = append(appointments, apt)
+	}
+
+	return appointments, nil
+}
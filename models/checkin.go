@@ -0,0 +1,62 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"klinik-app/events"
+)
+
+// ErrNotCheckable - Appointment bukan approved untuk hari ini, atau pasien sudah check-in
+var ErrNotCheckable = errors.New("hanya appointment approved hari ini yang belum check-in yang bisa dicatat kedatangannya")
+
+// GetAppointmentByNomorReg - Appointment berdasarkan nomor registrasi (ketik atau scan QR di loket)
+func GetAppointmentByNomorReg(db *sql.DB, nomorReg string) (*Appointment, error) {
+	var id int
+	err := db.QueryRow(`SELECT appointment_id FROM appointments WHERE nomor_registrasi = ?`, nomorReg).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return GetAppointmentByID(db, id)
+}
+
+// CheckInAppointment - Catat kedatangan pasien pada today (YYYY-MM-DD). Booking online yang belum punya
+// nomor antrian mendapat nomor berikutnya sesuai urutan kedatangan. Mengembalikan nomor antrian.
+func CheckInAppointment(db *sql.DB, appointmentID, checkedInBy int, today string) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var poliID, antrian sql.NullInt64
+	err = tx.QueryRow(`SELECT poli_id, nomor_antrian FROM appointments
+	                   WHERE appointment_id = ? AND status = 'approved' AND tanggal_konsultasi = ?
+	                     AND arrived_at IS NULL
+	                   FOR UPDATE`, appointmentID, today).Scan(&poliID, &antrian)
+	if err == sql.ErrNoRows {
+		return 0, ErrNotCheckable
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	nomor := int(antrian.Int64)
+	if !antrian.Valid {
+		if nomor, err = nextQueueNumber(tx, poliID, today); err != nil {
+			return 0, err
+		}
+	}
+
+	_, err = tx.Exec(`UPDATE appointments SET arrived_at = NOW(), checked_in_by = ?, nomor_antrian = ?
+	                  WHERE appointment_id = ?`, checkedInBy, nomor, appointmentID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	events.Publish(events.AppointmentCheckedIn, appointmentID)
+	return nomor, nil
+}
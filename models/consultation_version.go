@@ -0,0 +1,125 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type ConsultationVersion struct {
+	VersionID     int             `json:"version_id"`
+	AppointmentID int             `json:"appointment_id"`
+	Versi         int             `json:"versi"`
+	Gejala        sql.NullString  `json:"gejala"`
+	Diagnosa      sql.NullString  `json:"diagnosa"`
+	ResepObat     sql.NullString  `json:"resep_obat"`
+	TekananDarah  sql.NullString  `json:"tekanan_darah"`
+	Suhu          sql.NullFloat64 `json:"suhu"`
+	BeratBadan    sql.NullFloat64 `json:"berat_badan"`
+	Nadi          sql.NullInt64   `json:"nadi"`
+	Alasan        sql.NullString  `json:"alasan"`
+	CreatedBy     int             `json:"created_by"`
+	CreatedAt     time.Time       `json:"created_at"`
+
+	// Join fields
+	NamaPembuat string `json:"nama_pembuat,omitempty"`
+}
+
+// snapshotConsultation - Salin isi konsultasi saat ini sebagai versi baru
+func snapshotConsultation(tx *sql.Tx, appointmentID, userID int, alasan string) error {
+	var versi int
+	err := tx.QueryRow(`SELECT COALESCE(MAX(versi), 0) + 1 FROM consultation_versions
+	                    WHERE appointment_id = ? FOR UPDATE`, appointmentID).Scan(&versi)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO consultation_versions
+			(appointment_id, versi, gejala, diagnosa, resep_obat,
+			 tekanan_darah, suhu, berat_badan, nadi, alasan, created_by)
+		SELECT appointment_id, ?, gejala, diagnosa, resep_obat,
+		       tekanan_darah, suhu, berat_badan, nadi, NULLIF(?, ''), ?
+		FROM appointments WHERE appointment_id = ?
+	`
+	_, err = tx.Exec(query, versi, alasan, userID, appointmentID)
+	return err
+}
+
+// AmendConsultation - Dokter koreksi hasil konsultasi yang sudah selesai, versi lama tetap tersimpan
+func AmendConsultation(db *sql.DB, appointmentID, doctorID int, hasil HasilKonsultasi, alasan string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Konsultasi lama (sebelum ada fitur versi) disimpan dulu sebagai versi pertama
+	var count int
+	err = tx.QueryRow(`SELECT COUNT(*) FROM consultation_versions WHERE appointment_id = ?`,
+		appointmentID).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		if err := snapshotConsultation(tx, appointmentID, doctorID, ""); err != nil {
+			return err
+		}
+	}
+
+	if err := updateConsultation(tx, appointmentID, hasil, "completed", "completed"); err != nil {
+		return err
+	}
+	if err := snapshotConsultation(tx, appointmentID, doctorID, alasan); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetConsultationVersions - Riwayat versi hasil konsultasi, terbaru di atas
+func GetConsultationVersions(db *sql.DB, appointmentID int) ([]ConsultationVersion, error) {
+	query := `
+		SELECT
+			v.version_id, v.appointment_id, v.versi,
+			v.gejala, v.diagnosa, v.resep_obat,
+			v.tekanan_darah, v.suhu, v.berat_badan, v.nadi,
+			v.alasan, v.created_by, v.created_at,
+			u.nama AS nama_pembuat
+		FROM consultation_versions v
+		JOIN users u ON v.created_by = u.user_id
+		WHERE v.appointment_id = ?
+		ORDER BY v.versi DESC
+	`
+
+	rows, err := db.Query(query, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []ConsultationVersion
+	for rows.Next() {
+		var v ConsultationVersion
+		err := rows.Scan(
+			&v.VersionID,
+			&v.AppointmentID,
+			&v.Versi,
+			&v.Gejala,
+			&v.Diagnosa,
+			&v.ResepObat,
+			&v.TekananDarah,
+			&v.Suhu,
+			&v.BeratBadan,
+			&v.Nadi,
+			&v.Alasan,
+			&v.CreatedBy,
+			&v.CreatedAt,
+			&v.NamaPembuat,
+		)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"database/sql"
+)
+
+// DoctorSchedule - Jam praktik dokter pada satu hari dalam seminggu
+type DoctorSchedule struct {
+	DoctorID   int    `json:"doctor_id"`
+	Hari       int    `json:"hari"` // 0 = Minggu ... 6 = Sabtu (sama dengan time.Weekday)
+	JamMulai   string `json:"jam_mulai"`
+	JamSelesai string `json:"jam_selesai"`
+}
+
+// DoctorOnDuty - Dokter beserta jam praktiknya pada tanggal tertentu
+type DoctorOnDuty struct {
+	User
+	JamMulai   string `json:"jam_mulai,omitempty"`
+	JamSelesai string `json:"jam_selesai,omitempty"`
+}
+
+// GetDoctor - Profil dokter (poli & spesialisasi)
+func GetDoctor(db *sql.DB, doctorID int) (*User, error) {
+	var u User
+	err := db.QueryRow(`
+		SELECT u.user_id, u.nama, u.poli_id, COALESCE(p.nama, ''), u.spesialisasi
+		FROM users u
+		LEFT JOIN polyclinics p ON u.poli_id = p.poli_id
+		WHERE u.user_id = ? AND u.role = 'dokter'
+	`, doctorID).Scan(&u.UserID, &u.Nama, &u.PoliID, &u.NamaPoli, &u.Spesialisasi)
+	if err != nil {
+		return nil, err
+	}
+	u.Role = "dokter"
+	return &u, nil
+}
+
+// UpdateDoctorProfile - Admin mengatur poli (0 = tanpa poli) dan spesialisasi dokter
+func UpdateDoctorProfile(db *sql.DB, doctorID, poliID int, spesialisasi string) error {
+	_, err := db.Exec(`UPDATE users SET poli_id = NULLIF(?, 0), spesialisasi = NULLIF(?, '')
+	                   WHERE user_id = ? AND role = 'dokter'`, poliID, spesialisasi, doctorID)
+	return err
+}
+
+// GetDoctorSchedules - Jadwal praktik mingguan seorang dokter
+func GetDoctorSchedules(db *sql.DB, doctorID int) ([]DoctorSchedule, error) {
+	rows, err := db.Query(`
+		SELECT doctor_id, hari, TIME_FORMAT(jam_mulai, '%H:%i'), TIME_FORMAT(jam_selesai, '%H:%i')
+		FROM doctor_schedules
+		WHERE doctor_id = ?
+		ORDER BY hari ASC
+	`, doctorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []DoctorSchedule
+	for rows.Next() {
+		var s DoctorSchedule
+		if err := rows.Scan(&s.DoctorID, &s.Hari, &s.JamMulai, &s.JamSelesai); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, s)
+	}
+
+	return schedules, nil
+}
+
+// SaveDoctorSchedules - Ganti seluruh jadwal mingguan dokter
+func SaveDoctorSchedules(db *sql.DB, doctorID int, schedules []DoctorSchedule) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM doctor_schedules WHERE doctor_id = ?`, doctorID); err != nil {
+		return err
+	}
+	for _, s := range schedules {
+		_, err := tx.Exec(`INSERT INTO doctor_schedules (doctor_id, hari, jam_mulai, jam_selesai)
+		                   VALUES (?, ?, ?, ?)`, doctorID, s.Hari, s.JamMulai, s.JamSelesai)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetDoctorsOnDuty - Dokter poli tersebut (0 = semua poli) yang praktik pada tanggal (YYYY-MM-DD)
+func GetDoctorsOnDuty(db *sql.DB, poliID int, tanggal string) ([]DoctorOnDuty, error) {
+	rows, err := db.Query(`
+		SELECT u.user_id, u.nama, u.poli_id, p.nama, u.spesialisasi,
+		       TIME_FORMAT(s.jam_mulai, '%H:%i'), TIME_FORMAT(s.jam_selesai, '%H:%i')
+		FROM users u
+		JOIN polyclinics p ON u.poli_id = p.poli_id
+		JOIN doctor_schedules s ON s.doctor_id = u.user_id AND s.hari = DAYOFWEEK(?) - 1
+		WHERE u.role = 'dokter' AND (? = 0 OR u.poli_id = ?)
+		ORDER BY p.nama ASC, s.jam_mulai ASC, u.nama ASC
+	`, tanggal, poliID, poliID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var doctors []DoctorOnDuty
+	for rows.Next() {
+		var d DoctorOnDuty
+		err := rows.Scan(&d.UserID, &d.Nama, &d.PoliID, &d.NamaPoli, &d.Spesialisasi,
+			&d.JamMulai, &d.JamSelesai)
+		if err != nil {
+			return nil, err
+		}
+		d.Role = "dokter"
+		doctors = append(doctors, d)
+	}
+
+	return doctors, nil
+}
+
+// PoliHasSchedules - True jika minimal satu dokter poli tersebut sudah punya jadwal praktik
+func PoliHasSchedules(db *sql.DB, poliID int) (bool, error) {
+	var count int
+	err := db.QueryRow(`
+		SELECT COUNT(*) FROM doctor_schedules s
+		JOIN users u ON s.doctor_id = u.user_id
+		WHERE u.role = 'dokter' AND u.poli_id = ?
+	`, poliID).Scan(&count)
+	return count > 0, err
+}
@@ -0,0 +1,162 @@
+package models
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"math/big"
+	"time"
+)
+
+// Jenis dokumen yang bisa dicetak dari appointment yang sudah selesai
+const (
+	DokumenRingkasan  = "ringkasan"
+	DokumenResep      = "resep"
+	DokumenSuratSakit = "surat-sakit"
+)
+
+type Document struct {
+	DocumentID     int           `json:"document_id"`
+	AppointmentID  int           `json:"appointment_id"`
+	Jenis          string        `json:"jenis"`
+	KodeVerifikasi string        `json:"kode_verifikasi"`
+	HariIstirahat  sql.NullInt64 `json:"hari_istirahat"`
+	TanggalMulai   sql.NullTime  `json:"tanggal_mulai"`
+	CreatedBy      int           `json:"created_by"`
+	CreatedAt      time.Time     `json:"created_at"`
+
+	// Join fields
+	NomorRegistrasi   string    `json:"nomor_registrasi,omitempty"`
+	TanggalKonsultasi time.Time `json:"tanggal_konsultasi,omitempty"`
+	NamaPasien        string    `json:"nama_pasien,omitempty"`
+	NamaDokter        string    `json:"nama_dokter,omitempty"`
+}
+
+// TanggalSelesai - Hari terakhir istirahat pada surat sakit
+func (d Document) TanggalSelesai() time.Time {
+	return d.TanggalMulai.Time.AddDate(0, 0, int(d.HariIstirahat.Int64)-1)
+}
+
+// newVerificationCode - Kode acak format XXXX-XXXX-XXXX (tanpa huruf/angka yang mirip seperti O/0, I/1)
+func newVerificationCode() (string, error) {
+	const alphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	code := make([]byte, 0, 14)
+	for i := 0; i < 12; i++ {
+		if i > 0 && i%4 == 0 {
+			code = append(code, '-')
+		}
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			return "", err
+		}
+		code = append(code, alphabet[n.Int64()])
+	}
+	return string(code), nil
+}
+
+const documentQuery = `
+	SELECT
+		d.document_id, d.appointment_id, d.jenis, d.kode_verifikasi,
+		d.hari_istirahat, d.tanggal_mulai, d.created_by, d.created_at,
+		a.nomor_registrasi, a.tanggal_konsultasi,
+		up.nama AS nama_pasien, COALESCE(ud.nama, '') AS nama_dokter
+	FROM documents d
+	JOIN appointments a ON d.appointment_id = a.appointment_id
+	JOIN users up ON a.patient_id = up.user_id
+	LEFT JOIN users ud ON a.doctor_id = ud.user_id
+`
+
+func scanDocument(row *sql.Row) (*Document, error) {
+	var d Document
+	err := row.Scan(
+		&d.DocumentID,
+		&d.AppointmentID,
+		&d.Jenis,
+		&d.KodeVerifikasi,
+		&d.HariIstirahat,
+		&d.TanggalMulai,
+		&d.CreatedBy,
+		&d.CreatedAt,
+		&d.NomorRegistrasi,
+		&d.TanggalKonsultasi,
+		&d.NamaPasien,
+		&d.NamaDokter,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// GetDocument - Dokumen jenis tertentu untuk satu appointment
+func GetDocument(db *sql.DB, appointmentID int, jenis string) (*Document, error) {
+	return scanDocument(db.QueryRow(documentQuery+`WHERE d.appointment_id = ? AND d.jenis = ?`, appointmentID, jenis))
+}
+
+// GetDocumentByKode - Cari dokumen dari kode verifikasi (halaman verifikasi publik)
+func GetDocumentByKode(db *sql.DB, kode string) (*Document, error) {
+	return scanDocument(db.QueryRow(documentQuery+`WHERE d.kode_verifikasi = ?`, kode))
+}
+
+// GetOrCreateDocument - Ambil dokumen yang sudah ada, atau buat baru dengan kode verifikasi
+func GetOrCreateDocument(db *sql.DB, appointmentID int, jenis string, createdBy int) (*Document, error) {
+	kode, err := newVerificationCode()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec(`INSERT IGNORE INTO documents (appointment_id, jenis, kode_verifikasi, created_by)
+	                  VALUES (?, ?, ?, ?)`, appointmentID, jenis, kode, createdBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetDocument(db, appointmentID, jenis)
+}
+
+// IssueSickLeave - Dokter terbitkan surat keterangan sakit; terbit ulang akan mengganti kode verifikasi lama
+func IssueSickLeave(db *sql.DB, appointmentID, doctorID, hari int, tanggalMulai string) error {
+	kode, err := newVerificationCode()
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO documents (appointment_id, jenis, kode_verifikasi, hari_istirahat, tanggal_mulai, created_by)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			kode_verifikasi = VALUES(kode_verifikasi),
+			hari_istirahat = VALUES(hari_istirahat),
+			tanggal_mulai = VALUES(tanggal_mulai),
+			created_by = VALUES(created_by),
+			created_at = CURRENT_TIMESTAMP
+	`
+	_, err = db.Exec(query, appointmentID, DokumenSuratSakit, kode, hari, tanggalMulai, doctorID)
+	return err
+}
+
+// GetSickLeaveAppointmentIDs - Appointment pasien yang sudah punya surat sakit (untuk link di riwayat)
+func GetSickLeaveAppointmentIDs(db *sql.DB, patientID int) (map[int]bool, error) {
+	query := `
+		SELECT d.appointment_id
+		FROM documents d
+		JOIN appointments a ON d.appointment_id = a.appointment_id
+		WHERE a.patient_id = ? AND d.jenis = ?
+	`
+
+	rows, err := db.Query(query, patientID, DokumenSuratSakit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := map[int]bool{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids[id] = true
+	}
+
+	return ids, nil
+}
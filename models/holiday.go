@@ -0,0 +1,54 @@
+package models
+
+import (
+	"database/sql"
+)
+
+type Holiday struct {
+	Tanggal    string `json:"tanggal"` // YYYY-MM-DD
+	Keterangan string `json:"keterangan"`
+}
+
+// GetUpcomingHolidays - Hari libur mulai tanggal tertentu
+func GetUpcomingHolidays(db *sql.DB, fromDate string) ([]Holiday, error) {
+	rows, err := db.Query(`SELECT DATE_FORMAT(tanggal, '%Y-%m-%d'), keterangan FROM holidays
+	                       WHERE tanggal >= ? ORDER BY tanggal ASC`, fromDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var holidays []Holiday
+	for rows.Next() {
+		var h Holiday
+		if err := rows.Scan(&h.Tanggal, &h.Keterangan); err != nil {
+			return nil, err
+		}
+		holidays = append(holidays, h)
+	}
+
+	return holidays, nil
+}
+
+// GetHoliday - Keterangan hari libur pada tanggal tersebut (sql.ErrNoRows jika bukan hari libur)
+func GetHoliday(db *sql.DB, tanggal string) (*Holiday, error) {
+	h := Holiday{Tanggal: tanggal}
+	err := db.QueryRow(`SELECT keterangan FROM holidays WHERE tanggal = ?`, tanggal).Scan(&h.Keterangan)
+	if err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// SaveHoliday - Tambah hari libur (keterangan diperbarui jika tanggal sudah ada)
+func SaveHoliday(db *sql.DB, tanggal, keterangan string) error {
+	_, err := db.Exec(`INSERT INTO holidays (tanggal, keterangan) VALUES (?, ?)
+	                   ON DUPLICATE KEY UPDATE keterangan = VALUES(keterangan)`, tanggal, keterangan)
+	return err
+}
+
+// DeleteHoliday - Hapus hari libur
+func DeleteHoliday(db *sql.DB, tanggal string) error {
+	_, err := db.Exec(`DELETE FROM holidays WHERE tanggal = ?`, tanggal)
+	return err
+}
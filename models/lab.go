@@ -0,0 +1,291 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type LabOrder struct {
+	OrderID       int            `json:"order_id"`
+	AppointmentID int            `json:"appointment_id"`
+	PatientID     int            `json:"patient_id"`
+	DoctorID      int            `json:"doctor_id"`
+	Jenis         string         `json:"jenis"` // lab / radiologi
+	Pemeriksaan   string         `json:"pemeriksaan"`
+	Catatan       sql.NullString `json:"catatan"`
+	Status        string         `json:"status"` // ordered / completed
+	CreatedAt     time.Time      `json:"created_at"`
+	CompletedAt   sql.NullTime   `json:"completed_at"`
+
+	// Join fields
+	NamaPasien      string      `json:"nama_pasien,omitempty"`
+	NamaDokter      string      `json:"nama_dokter,omitempty"`
+	NomorRegistrasi string      `json:"nomor_registrasi,omitempty"`
+	Results         []LabResult `json:"results,omitempty"`
+}
+
+type LabResult struct {
+	ResultID   int             `json:"result_id"`
+	OrderID    int             `json:"order_id"`
+	Parameter  string          `json:"parameter"`
+	Nilai      sql.NullFloat64 `json:"nilai"`
+	Satuan     sql.NullString  `json:"satuan"`
+	RujukanMin sql.NullFloat64 `json:"rujukan_min"`
+	RujukanMax sql.NullFloat64 `json:"rujukan_max"`
+	FilePath   sql.NullString  `json:"-"` // key di storage
+	FileName   sql.NullString  `json:"file_name"`
+	MimeType   sql.NullString  `json:"mime_type"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// DiLuarRujukan - True jika nilai numerik berada di luar rentang rujukan
+func (r LabResult) DiLuarRujukan() bool {
+	if !r.Nilai.Valid {
+		return false
+	}
+	if r.RujukanMin.Valid && r.Nilai.Float64 < r.RujukanMin.Float64 {
+		return true
+	}
+	if r.RujukanMax.Valid && r.Nilai.Float64 > r.RujukanMax.Float64 {
+		return true
+	}
+	return false
+}
+
+// CreateLabOrder - Dokter membuat order pemeriksaan lab/radiologi dari form konsultasi
+func CreateLabOrder(db *sql.DB, appointmentID, patientID, doctorID int, jenis, pemeriksaan, catatan string) error {
+	query := `INSERT INTO lab_orders (appointment_id, patient_id, doctor_id, jenis, pemeriksaan, catatan, status)
+	          VALUES (?, ?, ?, ?, ?, NULLIF(?, ''), 'ordered')`
+
+	_, err := db.Exec(query, appointmentID, patientID, doctorID, jenis, pemeriksaan, catatan)
+	return err
+}
+
+const labOrderColumns = `
+	o.order_id, o.appointment_id, o.patient_id, o.doctor_id,
+	o.jenis, o.pemeriksaan, o.catatan, o.status,
+	o.created_at, o.completed_at,
+	up.nama AS nama_pasien, ud.nama AS nama_dokter, a.nomor_registrasi
+`
+
+const labOrderJoins = `
+	FROM lab_orders o
+	JOIN appointments a ON o.appointment_id = a.appointment_id
+	JOIN users up ON o.patient_id = up.user_id
+	JOIN users ud ON o.doctor_id = ud.user_id
+`
+
+func scanLabOrders(rows *sql.Rows) ([]LabOrder, error) {
+	defer rows.Close()
+
+	var orders []LabOrder
+	for rows.Next() {
+		var o LabOrder
+		err := rows.Scan(
+			&o.OrderID,
+			&o.AppointmentID,
+			&o.PatientID,
+			&o.DoctorID,
+			&o.Jenis,
+			&o.Pemeriksaan,
+			&o.Catatan,
+			&o.Status,
+			&o.CreatedAt,
+			&o.CompletedAt,
+			&o.NamaPasien,
+			&o.NamaDokter,
+			&o.NomorRegistrasi,
+		)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, o)
+	}
+
+	return orders, rows.Err()
+}
+
+// GetPendingLabOrders - Petugas lab melihat order yang belum ada hasilnya
+func GetPendingLabOrders(db *sql.DB) ([]LabOrder, error) {
+	rows, err := db.Query(`SELECT ` + labOrderColumns + labOrderJoins + `
+		WHERE o.status = 'ordered'
+		ORDER BY o.created_at ASC`)
+	if err != nil {
+		return nil, err
+	}
+	return scanLabOrders(rows)
+}
+
+// GetLabOrdersByPatient - Semua order pasien beserta hasilnya (untuk riwayat & konsultasi berikutnya)
+func GetLabOrdersByPatient(db *sql.DB, patientID int) ([]LabOrder, error) {
+	rows, err := db.Query(`SELECT `+labOrderColumns+labOrderJoins+`
+		WHERE o.patient_id = ?
+		ORDER BY o.created_at DESC`, patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := scanLabOrders(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range orders {
+		orders[i].Results, err = GetLabResults(db, orders[i].OrderID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return orders, nil
+}
+
+// GetLabOrderByID - Detail satu order pemeriksaan
+func GetLabOrderByID(db *sql.DB, orderID int) (*LabOrder, error) {
+	rows, err := db.Query(`SELECT `+labOrderColumns+labOrderJoins+`
+		WHERE o.order_id = ?`, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := scanLabOrders(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	order := orders[0]
+	order.Results, err = GetLabResults(db, order.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// GetLabResults - Hasil pemeriksaan untuk satu order
+func GetLabResults(db *sql.DB, orderID int) ([]LabResult, error) {
+	query := `
+		SELECT result_id, order_id, parameter, nilai, satuan,
+		       rujukan_min, rujukan_max, file_path, file_name, mime_type, created_at
+		FROM lab_results
+		WHERE order_id = ?
+		ORDER BY result_id ASC
+	`
+
+	rows, err := db.Query(query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []LabResult
+	for rows.Next() {
+		var res LabResult
+		err := rows.Scan(
+			&res.ResultID,
+			&res.OrderID,
+			&res.Parameter,
+			&res.Nilai,
+			&res.Satuan,
+			&res.RujukanMin,
+			&res.RujukanMax,
+			&res.FilePath,
+			&res.FileName,
+			&res.MimeType,
+			&res.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, res)
+	}
+
+	return results, nil
+}
+
+// GetLabResultByID - Satu hasil pemeriksaan (untuk download file)
+func GetLabResultByID(db *sql.DB, resultID int) (*LabResult, error) {
+	var res LabResult
+	query := `
+		SELECT result_id, order_id, parameter, nilai, satuan,
+		       rujukan_min, rujukan_max, file_path, file_name, mime_type, created_at
+		FROM lab_results
+		WHERE result_id = ?
+	`
+
+	err := db.QueryRow(query, resultID).Scan(
+		&res.ResultID,
+		&res.OrderID,
+		&res.Parameter,
+		&res.Nilai,
+		&res.Satuan,
+		&res.RujukanMin,
+		&res.RujukanMax,
+		&res.FilePath,
+		&res.FileName,
+		&res.MimeType,
+		&res.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// ErrLabOrderClosed - Order lab sudah diselesaikan (misalnya oleh petugas lain)
+var ErrLabOrderClosed = errors.New("hasil order ini sudah diinput")
+
+// LabResultInput - Satu hasil pemeriksaan yang diinput petugas lab; FilePath terisi untuk hasil berupa file
+type LabResultInput struct {
+	Parameter  string
+	Nilai      string
+	Satuan     string
+	RujukanMin string
+	RujukanMax string
+	FilePath   string // key di storage
+	FileName   string
+	MimeType   string
+}
+
+// CompleteLabOrder - Simpan semua hasil dan tandai order selesai dalam satu transaksi.
+// Hanya order berstatus ordered yang bisa diselesaikan; selain itu ErrLabOrderClosed.
+func CompleteLabOrder(db *sql.DB, orderID, labUserID int, results []LabResultInput) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`UPDATE lab_orders
+	                     SET status = 'completed', completed_at = NOW(), completed_by = ?
+	                     WHERE order_id = ? AND status = 'ordered'`, labUserID, orderID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n != 1 {
+		return ErrLabOrderClosed
+	}
+
+	for _, r := range results {
+		if r.FilePath != "" {
+			_, err = tx.Exec(`INSERT INTO lab_results (order_id, parameter, file_path, file_name, mime_type)
+			                  VALUES (?, ?, ?, ?, ?)`, orderID, r.Parameter, r.FilePath, r.FileName, r.MimeType)
+		} else {
+			_, err = tx.Exec(`INSERT INTO lab_results (order_id, parameter, nilai, satuan, rujukan_min, rujukan_max)
+			                  VALUES (?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''))`,
+				orderID, r.Parameter, r.Nilai, r.Satuan, r.RujukanMin, r.RujukanMax)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
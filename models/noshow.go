@@ -0,0 +1,69 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"klinik-app/events"
+)
+
+// MarkNoShow - Dokter menandai pasien tidak hadir (hanya appointment approved miliknya, hari ini atau sebelumnya).
+// today adalah tanggal hari ini (YYYY-MM-DD) di zona waktu klinik
+func MarkNoShow(db *sql.DB, appointmentID, doctorID int, today string) error {
+	res, err := db.Exec(`UPDATE appointments SET status = 'no_show'
+	                     WHERE appointment_id = ? AND doctor_id = ? AND status = 'approved'
+	                       AND tanggal_konsultasi <= ?`, appointmentID, doctorID, today)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n != 1 {
+		return errors.New("hanya appointment approved hari ini atau sebelumnya yang bisa ditandai tidak hadir")
+	}
+
+	events.Publish(events.AppointmentNoShow, appointmentID)
+	return nil
+}
+
+// MarkPastNoShows - Appointment approved yang tanggalnya sebelum today (YYYY-MM-DD) otomatis menjadi no_show
+func MarkPastNoShows(db *sql.DB, today string) (int, error) {
+	rows, err := db.Query(`SELECT appointment_id FROM appointments
+	                       WHERE status = 'approved' AND tanggal_konsultasi < ?`, today)
+	if err != nil {
+		return 0, err
+	}
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	marked := 0
+	for _, id := range ids {
+		res, err := db.Exec(`UPDATE appointments SET status = 'no_show'
+		                     WHERE appointment_id = ? AND status = 'approved'`, id)
+		if err != nil {
+			return marked, err
+		}
+		if n, _ := res.RowsAffected(); n == 1 {
+			marked++
+			events.Publish(events.AppointmentNoShow, id)
+		}
+	}
+
+	return marked, nil
+}
+
+// CountNoShows - Jumlah ketidakhadiran pasien dalam sekian hari terakhir sebelum today (YYYY-MM-DD)
+func CountNoShows(db Querier, patientID, windowDays int, today string) (int, error) {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM appointments
+	                    WHERE patient_id = ? AND status = 'no_show'
+	                      AND tanggal_konsultasi >= ? - INTERVAL ? DAY`,
+		patientID, today, windowDays).Scan(&count)
+	return count, err
+}
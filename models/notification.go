@@ -0,0 +1,98 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Notification struct {
+	NotificationID int       `json:"notification_id"`
+	UserID         int       `json:"user_id"`
+	Judul          string    `json:"judul"`
+	Pesan          string    `json:"pesan"`
+	Link           string    `json:"link"`
+	Dibaca         bool      `json:"dibaca"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// CreateNotification - Notifikasi in-app untuk satu user
+func CreateNotification(db *sql.DB, userID int, judul, pesan, link string) error {
+	query := `INSERT INTO notifications (user_id, judul, pesan, link) VALUES (?, ?, ?, ?)`
+
+	_, err := db.Exec(query, userID, judul, pesan, link)
+	return err
+}
+
+// CreateNotificationForRole - Notifikasi in-app untuk semua user dengan role tertentu (misal semua admin)
+func CreateNotificationForRole(db *sql.DB, role, judul, pesan, link string) error {
+	query := `INSERT INTO notifications (user_id, judul, pesan, link)
+	          SELECT user_id, ?, ?, ? FROM users WHERE role = ?`
+
+	_, err := db.Exec(query, judul, pesan, link, role)
+	return err
+}
+
+// GetNotifications - Notifikasi terbaru milik user
+func GetNotifications(db *sql.DB, userID, limit int) ([]Notification, error) {
+	query := `
+		SELECT notification_id, user_id, judul, pesan, link, dibaca, created_at
+		FROM notifications
+		WHERE user_id = ?
+		ORDER BY created_at DESC, notification_id DESC
+		LIMIT ?
+	`
+
+	rows, err := db.Query(query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []Notification
+	for rows.Next() {
+		var n Notification
+		err := rows.Scan(
+			&n.NotificationID,
+			&n.UserID,
+			&n.Judul,
+			&n.Pesan,
+			&n.Link,
+			&n.Dibaca,
+			&n.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	return notifications, nil
+}
+
+// CountUnreadNotifications - Jumlah notifikasi belum dibaca (untuk badge di header)
+func CountUnreadNotifications(db *sql.DB, userID int) (int, error) {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM notifications WHERE user_id = ? AND dibaca = 0`,
+		userID).Scan(&count)
+	return count, err
+}
+
+// MarkNotificationRead - Tandai satu notifikasi sudah dibaca, kembalikan link tujuannya
+func MarkNotificationRead(db *sql.DB, notificationID, userID int) (string, error) {
+	var link string
+	err := db.QueryRow(`SELECT link FROM notifications WHERE notification_id = ? AND user_id = ?`,
+		notificationID, userID).Scan(&link)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = db.Exec(`UPDATE notifications SET dibaca = 1 WHERE notification_id = ? AND user_id = ?`,
+		notificationID, userID)
+	return link, err
+}
+
+// MarkAllNotificationsRead - Tandai semua notifikasi user sudah dibaca
+func MarkAllNotificationsRead(db *sql.DB, userID int) error {
+	_, err := db.Exec(`UPDATE notifications SET dibaca = 1 WHERE user_id = ? AND dibaca = 0`, userID)
+	return err
+}
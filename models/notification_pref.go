@@ -0,0 +1,43 @@
+package models
+
+import (
+	"database/sql"
+)
+
+// GetDisabledChannels - Channel notifikasi yang dimatikan user (default semua aktif)
+func GetDisabledChannels(db *sql.DB, userID int) (map[string]bool, error) {
+	rows, err := db.Query(`SELECT channel FROM notification_preferences
+	                       WHERE user_id = ? AND aktif = 0`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	disabled := map[string]bool{}
+	for rows.Next() {
+		var channel string
+		if err := rows.Scan(&channel); err != nil {
+			return nil, err
+		}
+		disabled[channel] = true
+	}
+
+	return disabled, nil
+}
+
+// SetChannelPreference - Aktifkan/matikan satu channel notifikasi untuk user
+func SetChannelPreference(db *sql.DB, userID int, channel string, aktif bool) error {
+	query := `INSERT INTO notification_preferences (user_id, channel, aktif) VALUES (?, ?, ?)
+	          ON DUPLICATE KEY UPDATE aktif = VALUES(aktif)`
+
+	_, err := db.Exec(query, userID, channel, aktif)
+	return err
+}
+
+// UpdateUserContact - Ubah email & no HP user (kosong = hapus)
+func UpdateUserContact(db *sql.DB, userID int, email, noHP string) error {
+	query := `UPDATE users SET email = NULLIF(?, ''), no_hp = NULLIF(?, '') WHERE user_id = ?`
+
+	_, err := db.Exec(query, email, noHP, userID)
+	return err
+}
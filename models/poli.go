@@ -0,0 +1,79 @@
+package models
+
+import (
+	"database/sql"
+)
+
+type Poli struct {
+	PoliID int    `json:"poli_id"`
+	Kode   string `json:"kode"`
+	Nama   string `json:"nama"`
+	Aktif  bool   `json:"aktif"`
+
+	JumlahDokter int `json:"jumlah_dokter,omitempty"`
+}
+
+// GetPolis - Semua poli beserta jumlah dokternya (untuk halaman admin)
+func GetPolis(db *sql.DB) ([]Poli, error) {
+	return queryPolis(db, `
+		SELECT p.poli_id, p.kode, p.nama, p.aktif, COUNT(u.user_id)
+		FROM polyclinics p
+		LEFT JOIN users u ON u.poli_id = p.poli_id AND u.role = 'dokter'
+		GROUP BY p.poli_id, p.kode, p.nama, p.aktif
+		ORDER BY p.nama ASC
+	`)
+}
+
+// GetActivePolis - Poli yang bisa dipilih pasien saat booking
+func GetActivePolis(db *sql.DB) ([]Poli, error) {
+	return queryPolis(db, `
+		SELECT p.poli_id, p.kode, p.nama, p.aktif, COUNT(u.user_id)
+		FROM polyclinics p
+		LEFT JOIN users u ON u.poli_id = p.poli_id AND u.role = 'dokter'
+		WHERE p.aktif = 1
+		GROUP BY p.poli_id, p.kode, p.nama, p.aktif
+		ORDER BY p.nama ASC
+	`)
+}
+
+func queryPolis(db *sql.DB, query string) ([]Poli, error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var polis []Poli
+	for rows.Next() {
+		var p Poli
+		if err := rows.Scan(&p.PoliID, &p.Kode, &p.Nama, &p.Aktif, &p.JumlahDokter); err != nil {
+			return nil, err
+		}
+		polis = append(polis, p)
+	}
+
+	return polis, nil
+}
+
+// GetPoli - Detail satu poli
+func GetPoli(db *sql.DB, poliID int) (*Poli, error) {
+	var p Poli
+	err := db.QueryRow(`SELECT poli_id, kode, nama, aktif FROM polyclinics WHERE poli_id = ?`, poliID).
+		Scan(&p.PoliID, &p.Kode, &p.Nama, &p.Aktif)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// CreatePoli - Tambah poli baru (kode unik, misal "gigi")
+func CreatePoli(db *sql.DB, kode, nama string) error {
+	_, err := db.Exec(`INSERT INTO polyclinics (kode, nama) VALUES (?, ?)`, kode, nama)
+	return err
+}
+
+// SetPoliActive - Aktifkan/nonaktifkan poli; poli nonaktif tidak muncul di form booking
+func SetPoliActive(db *sql.DB, poliID int, aktif bool) error {
+	_, err := db.Exec(`UPDATE polyclinics SET aktif = ? WHERE poli_id = ?`, aktif, poliID)
+	return err
+}
@@ -0,0 +1,16 @@
+package models
+
+import "database/sql"
+
+// NextRegistrationSequence - Urutan nomor registrasi berikutnya pada tanggal (YYYY-MM-DD), mulai dari 1.
+// Dinaikkan secara atomik di DB sehingga booking bersamaan tidak pernah mendapat urutan yang sama.
+func NextRegistrationSequence(db *sql.DB, tanggal string) (int, error) {
+	res, err := db.Exec(`INSERT INTO registration_sequences (tanggal, nomor_terakhir) VALUES (?, LAST_INSERT_ID(1))
+	                     ON DUPLICATE KEY UPDATE nomor_terakhir = LAST_INSERT_ID(nomor_terakhir + 1)`, tanggal)
+	if err != nil {
+		return 0, err
+	}
+
+	seq, err := res.LastInsertId()
+	return int(seq), err
+}
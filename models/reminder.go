@@ -0,0 +1,72 @@
+package models
+
+import (
+	"database/sql"
+)
+
+// GetApprovedAppointmentsBetween - Appointment approved dengan waktu yang sudah ditentukan dalam rentang tanggal
+func GetApprovedAppointmentsBetween(db *sql.DB, fromDate, toDate string) ([]Appointment, error) {
+	query := `
+		SELECT
+			a.appointment_id, a.nomor_registrasi, a.patient_id,
+			a.tanggal_konsultasi, a.waktu_konsultasi,
+			up.nama AS nama_pasien, COALESCE(ud.nama, '') AS nama_dokter
+		FROM appointments a
+		JOIN users up ON a.patient_id = up.user_id
+		LEFT JOIN users ud ON a.doctor_id = ud.user_id
+		WHERE a.status = 'approved'
+		  AND a.waktu_konsultasi IS NOT NULL
+		  AND DATE(a.tanggal_konsultasi) BETWEEN ? AND ?
+	`
+
+	rows, err := db.Query(query, fromDate, toDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var appointments []Appointment
+	for rows.Next() {
+		var apt Appointment
+		err := rows.Scan(
+			&apt.AppointmentID,
+			&apt.NomorRegistrasi,
+			&apt.PatientID,
+			&apt.TanggalKonsultasi,
+			&apt.WaktuKonsultasi,
+			&apt.NamaPasien,
+			&apt.NamaDokter,
+		)
+		if err != nil {
+			return nil, err
+		}
+		appointments = append(appointments, apt)
+	}
+
+	return appointments, nil
+}
+
+// ClaimReminder - Tandai pengingat sebagai terkirim; false jika sudah pernah (aman saat server restart)
+func ClaimReminder(db *sql.DB, appointmentID int, jenis, channel string) (bool, error) {
+	res, err := db.Exec(`INSERT IGNORE INTO appointment_reminders (appointment_id, jenis, channel)
+	                     VALUES (?, ?, ?)`, appointmentID, jenis, channel)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// ReleaseReminder - Batalkan tanda terkirim jika pengiriman gagal, supaya dicoba lagi
+func ReleaseReminder(db *sql.DB, appointmentID int, jenis, channel string) error {
+	_, err := db.Exec(`DELETE FROM appointment_reminders
+	                   WHERE appointment_id = ? AND jenis = ? AND channel = ?`, appointmentID, jenis, channel)
+	return err
+}
+
+// resetReminders - Hapus tanda pengingat terkirim setelah jadwal berubah, supaya slot baru diingatkan lagi
+func resetReminders(tx *sql.Tx, appointmentID int) error {
+	_, err := tx.Exec(`DELETE FROM appointment_reminders WHERE appointment_id = ?`, appointmentID)
+	return err
+}
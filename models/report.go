@@ -0,0 +1,44 @@
+package models
+
+import (
+	"database/sql"
+)
+
+// CancellationStat - Jumlah pembatalan untuk satu alasan, dipisah menurut siapa yang membatalkan
+type CancellationStat struct {
+	Alasan     string
+	OlehPasien int
+	OlehKlinik int
+	Total      int
+}
+
+// GetCancellationStats - Rekap pembatalan per alasan dalam rentang tanggal (YYYY-MM-DD, inklusif).
+// Pembatalan lama tanpa alasan/waktu/pembatal dihitung sebagai "(tanpa alasan)" oleh klinik pada tanggal konsultasinya.
+func GetCancellationStats(db *sql.DB, from, to string) ([]CancellationStat, error) {
+	rows, err := db.Query(`
+		SELECT COALESCE(a.alasan_batal, '(tanpa alasan)') AS alasan,
+		       SUM(CASE WHEN uc.role = 'pasien' THEN 1 ELSE 0 END),
+		       SUM(CASE WHEN uc.role = 'pasien' THEN 0 ELSE 1 END), COUNT(*)
+		FROM appointments a
+		LEFT JOIN users uc ON a.cancelled_by = uc.user_id
+		WHERE a.status = 'cancelled'
+		  AND DATE(COALESCE(a.cancelled_at, a.tanggal_konsultasi)) BETWEEN ? AND ?
+		GROUP BY alasan
+		ORDER BY COUNT(*) DESC, alasan ASC
+	`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []CancellationStat
+	for rows.Next() {
+		var s CancellationStat
+		if err := rows.Scan(&s.Alasan, &s.OlehPasien, &s.OlehKlinik, &s.Total); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+
+	return stats, nil
+}
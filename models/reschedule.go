@@ -0,0 +1,134 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"klinik-app/events"
+)
+
+// ErrSlotTaken - Jam yang dipilih sudah terisi pasien lain
+var ErrSlotTaken = errors.New("jam tersebut sudah terisi, silakan pilih jam lain")
+
+// ErrNotReschedulable - Appointment sudah berubah status atau batas perubahan jadwal tercapai sejak form dibuka
+var ErrNotReschedulable = errors.New("jadwal appointment ini sudah tidak bisa diubah (status berubah atau batas perubahan jadwal tercapai)")
+
+// GetDoctorBookedTimes - Jam (HH:MM) yang sudah terisi appointment aktif dokter pada tanggal tertentu
+func GetDoctorBookedTimes(db *sql.DB, doctorID int, tanggal string, excludeAppointmentID int) (map[string]bool, error) {
+	rows, err := db.Query(`SELECT TIME_FORMAT(waktu_konsultasi, '%H:%i') FROM appointments
+	                       WHERE doctor_id = ? AND tanggal_konsultasi = ? AND waktu_konsultasi IS NOT NULL
+	                         AND status IN ('approved', 'in_progress', 'completed')
+	                         AND appointment_id <> ?`, doctorID, tanggal, excludeAppointmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	booked := map[string]bool{}
+	for rows.Next() {
+		var waktu string
+		if err := rows.Scan(&waktu); err != nil {
+			return nil, err
+		}
+		booked[waktu] = true
+	}
+
+	return booked, nil
+}
+
+// lockForReschedule - Kunci baris appointment lalu tanggal tujuan & pasien, kemudian jalankan check (kuota tanggal tujuan)
+func lockForReschedule(tx *sql.Tx, appointmentID, patientID int, tanggal string, check func(tx *sql.Tx) error) error {
+	var locked int
+	err := tx.QueryRow(`SELECT appointment_id FROM appointments WHERE appointment_id = ? AND patient_id = ? FOR UPDATE`,
+		appointmentID, patientID).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return ErrNotReschedulable
+	}
+	if err != nil {
+		return err
+	}
+	return lockBookingDate(tx, patientID, tanggal, check)
+}
+
+// RescheduleToPending - Pasien pindah tanggal; dokter & jam dilepas dan appointment menunggu persetujuan ulang
+func RescheduleToPending(db *sql.DB, appointmentID, patientID, maxReschedule int, tanggal string, check func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockForReschedule(tx, appointmentID, patientID, tanggal, check); err != nil {
+		return err
+	}
+
+	res, err := tx.Exec(`UPDATE appointments
+	                     SET tanggal_konsultasi = ?, doctor_id = NULL, waktu_konsultasi = NULL,
+	                         status = 'pending', reschedule_count = reschedule_count + 1
+	                     WHERE appointment_id = ? AND patient_id = ? AND status IN ('pending', 'approved')
+	                       AND reschedule_count < ?`,
+		tanggal, appointmentID, patientID, maxReschedule)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n != 1 {
+		return ErrNotReschedulable
+	}
+	if err := resetReminders(tx, appointmentID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	events.Publish(events.AppointmentRescheduled, appointmentID)
+	return nil
+}
+
+// RescheduleToSlot - Pasien pindah ke slot kosong dokter yang sama; tetap approved
+func RescheduleToSlot(db *sql.DB, appointmentID, patientID, doctorID, maxReschedule int, tanggal, waktu string, check func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockForReschedule(tx, appointmentID, patientID, tanggal, check); err != nil {
+		return err
+	}
+
+	var count int
+	err = tx.QueryRow(`SELECT COUNT(*) FROM appointments
+	                   WHERE doctor_id = ? AND tanggal_konsultasi = ? AND waktu_konsultasi = ?
+	                     AND status IN ('approved', 'in_progress', 'completed') AND appointment_id <> ?
+	                   FOR UPDATE`, doctorID, tanggal, waktu, appointmentID).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrSlotTaken
+	}
+
+	res, err := tx.Exec(`UPDATE appointments
+	                     SET tanggal_konsultasi = ?, waktu_konsultasi = ?,
+	                         reschedule_count = reschedule_count + 1
+	                     WHERE appointment_id = ? AND patient_id = ? AND doctor_id = ? AND status = 'approved'
+	                       AND reschedule_count < ?`,
+		tanggal, waktu, appointmentID, patientID, doctorID, maxReschedule)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n != 1 {
+		return ErrNotReschedulable
+	}
+	if err := resetReminders(tx, appointmentID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	events.Publish(events.AppointmentRescheduled, appointmentID)
+	return nil
+}
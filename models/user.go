@@ -12,6 +12,15 @@ type User struct {
 	Password  string    `json:"-"`
 	Role      string    `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
+
+	// Kontak untuk notifikasi (opsional)
+	Email sql.NullString `json:"email"`
+	NoHP  sql.NullString `json:"no_hp"`
+
+	// Khusus dokter: poli tempat praktik dan spesialisasi
+	PoliID       sql.NullInt64  `json:"poli_id"`
+	NamaPoli     string         `json:"nama_poli,omitempty"`
+	Spesialisasi sql.NullString `json:"spesialisasi"`
 }
 
 // GetUserByNIK - Mendapatkan user berdasarkan NIK
@@ -37,9 +46,37 @@ func GetUserByNIK(db *sql.DB, nik string) (*User, error) {
 	return &user, nil
 }
 
-// GetDoctors - Mendapatkan semua dokter (untuk dropdown admin)
+// GetUserByID - Mendapatkan user berdasarkan ID (untuk profil pasien)
+func GetUserByID(db *sql.DB, userID int) (*User, error) {
+	var user User
+
+	query := `SELECT user_id, nik, nama, role, created_at, email, no_hp 
+	          FROM users WHERE user_id = ?`
+
+	err := db.QueryRow(query, userID).Scan(
+		&user.UserID,
+		&user.NIK,
+		&user.Nama,
+		&user.Role,
+		&user.CreatedAt,
+		&user.Email,
+		&user.NoHP,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// GetDoctors - Mendapatkan semua dokter beserta poli & spesialisasinya (untuk dropdown admin)
 func GetDoctors(db *sql.DB) ([]User, error) {
-	query := `SELECT user_id, nama FROM users WHERE role = 'dokter'`
+	query := `SELECT u.user_id, u.nama, u.poli_id, COALESCE(p.nama, ''), u.spesialisasi
+	          FROM users u
+	          LEFT JOIN polyclinics p ON u.poli_id = p.poli_id
+	          WHERE u.role = 'dokter'
+	          ORDER BY u.nama ASC`
 
 	rows, err := db.Query(query)
 	if err != nil {
@@ -50,7 +87,7 @@ func GetDoctors(db *sql.DB) ([]User, error) {
 	var doctors []User
 	for rows.Next() {
 		var doctor User
-		err := rows.Scan(&doctor.UserID, &doctor.Nama)
+		err := rows.Scan(&doctor.UserID, &doctor.Nama, &doctor.PoliID, &doctor.NamaPoli, &doctor.Spesialisasi)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,330 @@
+package models
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"klinik-app/events"
+	"time"
+)
+
+// ErrOfferUnavailable - Tawaran slot sudah kedaluwarsa, sudah diklaim, atau slotnya sudah terisi
+var ErrOfferUnavailable = errors.New("tawaran slot sudah tidak tersedia")
+
+type WaitlistEntry struct {
+	WaitlistID int           `json:"waitlist_id"`
+	PatientID  int           `json:"patient_id"`
+	Tanggal    time.Time     `json:"tanggal"`
+	DoctorID   sql.NullInt64 `json:"doctor_id"` // kosong = dokter mana saja
+	Status     string        `json:"status"`    // waiting / claimed / cancelled
+	CreatedAt  time.Time     `json:"created_at"`
+
+	// Join fields
+	NamaDokter string `json:"nama_dokter,omitempty"`
+}
+
+type WaitlistOffer struct {
+	OfferID             int           `json:"offer_id"`
+	WaitlistID          int           `json:"waitlist_id"`
+	SourceAppointmentID int           `json:"source_appointment_id"`
+	DoctorID            int           `json:"doctor_id"`
+	Tanggal             time.Time     `json:"tanggal"`
+	Waktu               string        `json:"waktu"`
+	Token               string        `json:"-"`
+	Status              string        `json:"status"` // offered / claimed / expired
+	ExpiresAt           time.Time     `json:"expires_at"`
+	AppointmentID       sql.NullInt64 `json:"appointment_id"`
+	Aktif               bool          `json:"aktif"` // masih bisa diklaim (dihitung dengan jam database)
+
+	// Join fields
+	PatientID  int    `json:"patient_id"`
+	NamaDokter string `json:"nama_dokter,omitempty"`
+}
+
+// JoinWaitlist - Pasien masuk daftar tunggu untuk tanggal (dan dokter, 0 = siapa saja)
+func JoinWaitlist(db *sql.DB, patientID, doctorID int, tanggal string) error {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM waitlist
+	                    WHERE patient_id = ? AND tanggal = ? AND status = 'waiting'
+	                      AND COALESCE(doctor_id, 0) = ?`, patientID, tanggal, doctorID).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("Anda sudah terdaftar di daftar tunggu untuk tanggal ini")
+	}
+
+	_, err = db.Exec(`INSERT INTO waitlist (patient_id, tanggal, doctor_id) VALUES (?, ?, NULLIF(?, 0))`,
+		patientID, tanggal, doctorID)
+	return err
+}
+
+// LeaveWaitlist - Pasien keluar dari daftar tunggu
+func LeaveWaitlist(db *sql.DB, waitlistID, patientID int) error {
+	_, err := db.Exec(`UPDATE waitlist SET status = 'cancelled'
+	                   WHERE waitlist_id = ? AND patient_id = ? AND status = 'waiting'`, waitlistID, patientID)
+	return err
+}
+
+// GetPatientWaitlist - Daftar tunggu pasien yang masih aktif (tanggal today atau sesudahnya)
+func GetPatientWaitlist(db *sql.DB, patientID int, today string) ([]WaitlistEntry, error) {
+	query := `
+		SELECT w.waitlist_id, w.patient_id, w.tanggal, w.doctor_id, w.status, w.created_at,
+		       COALESCE(u.nama, '') AS nama_dokter
+		FROM waitlist w
+		LEFT JOIN users u ON w.doctor_id = u.user_id
+		WHERE w.patient_id = ? AND w.status = 'waiting' AND w.tanggal >= ?
+		ORDER BY w.tanggal ASC
+	`
+
+	rows, err := db.Query(query, patientID, today)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []WaitlistEntry
+	for rows.Next() {
+		var e WaitlistEntry
+		err := rows.Scan(
+			&e.WaitlistID,
+			&e.PatientID,
+			&e.Tanggal,
+			&e.DoctorID,
+			&e.Status,
+			&e.CreatedAt,
+			&e.NamaDokter,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+// NextWaitlistEntry - Antrian berikutnya yang cocok untuk slot yang dibatalkan:
+// tanggal & dokter sesuai, belum pernah ditawari slot ini, tidak sedang memegang tawaran lain,
+// dan belum punya appointment aktif di tanggal itu
+func NextWaitlistEntry(db *sql.DB, sourceAppointmentID, doctorID int, tanggal string) (*WaitlistEntry, error) {
+	query := `
+		SELECT w.waitlist_id, w.patient_id, w.tanggal, w.doctor_id, w.status, w.created_at
+		FROM waitlist w
+		WHERE w.status = 'waiting'
+		  AND w.tanggal = ?
+		  AND (w.doctor_id IS NULL OR w.doctor_id = ?)
+		  AND NOT EXISTS (SELECT 1 FROM waitlist_offers o
+		                  WHERE o.waitlist_id = w.waitlist_id
+		                    AND (o.source_appointment_id = ? OR o.status = 'offered'))
+		  AND NOT EXISTS (SELECT 1 FROM appointments a
+		                  WHERE a.patient_id = w.patient_id AND a.tanggal_konsultasi = w.tanggal
+		                    AND a.status IN ('pending', 'approved', 'in_progress'))
+		ORDER BY w.created_at ASC, w.waitlist_id ASC
+		LIMIT 1
+	`
+
+	var e WaitlistEntry
+	err := db.QueryRow(query, tanggal, doctorID, sourceAppointmentID).Scan(
+		&e.WaitlistID,
+		&e.PatientID,
+		&e.Tanggal,
+		&e.DoctorID,
+		&e.Status,
+		&e.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// CreateWaitlistOffer - Tawarkan slot ke satu entri waitlist, berlaku selama ttl
+func CreateWaitlistOffer(db *sql.DB, waitlistID, sourceAppointmentID, doctorID int, tanggal, waktu string, ttl time.Duration) (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+
+	_, err := db.Exec(`INSERT INTO waitlist_offers
+	                   (waitlist_id, source_appointment_id, doctor_id, tanggal, waktu, token, expires_at)
+	                   VALUES (?, ?, ?, ?, ?, ?, NOW() + INTERVAL ? SECOND)`,
+		waitlistID, sourceAppointmentID, doctorID, tanggal, waktu, token, int(ttl.Seconds()))
+	return token, err
+}
+
+const waitlistOfferColumns = `
+	o.offer_id, o.waitlist_id, o.source_appointment_id, o.doctor_id, o.tanggal, o.waktu,
+	o.token, o.status, o.expires_at, o.appointment_id,
+	(o.status = 'offered' AND o.expires_at > NOW()) AS aktif,
+	w.patient_id, u.nama AS nama_dokter
+`
+
+const waitlistOfferJoins = `
+	FROM waitlist_offers o
+	JOIN waitlist w ON o.waitlist_id = w.waitlist_id
+	JOIN users u ON o.doctor_id = u.user_id
+`
+
+func scanWaitlistOffers(rows *sql.Rows) ([]WaitlistOffer, error) {
+	defer rows.Close()
+
+	var offers []WaitlistOffer
+	for rows.Next() {
+		var o WaitlistOffer
+		err := rows.Scan(
+			&o.OfferID,
+			&o.WaitlistID,
+			&o.SourceAppointmentID,
+			&o.DoctorID,
+			&o.Tanggal,
+			&o.Waktu,
+			&o.Token,
+			&o.Status,
+			&o.ExpiresAt,
+			&o.AppointmentID,
+			&o.Aktif,
+			&o.PatientID,
+			&o.NamaDokter,
+		)
+		if err != nil {
+			return nil, err
+		}
+		offers = append(offers, o)
+	}
+
+	return offers, rows.Err()
+}
+
+// GetWaitlistOfferByToken - Tawaran dari link klaim
+func GetWaitlistOfferByToken(db *sql.DB, token string) (*WaitlistOffer, error) {
+	rows, err := db.Query(`SELECT `+waitlistOfferColumns+waitlistOfferJoins+`WHERE o.token = ?`, token)
+	if err != nil {
+		return nil, err
+	}
+
+	offers, err := scanWaitlistOffers(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(offers) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return &offers[0], nil
+}
+
+// GetPatientWaitlistOffers - Tawaran slot yang masih bisa diklaim pasien
+func GetPatientWaitlistOffers(db *sql.DB, patientID int) ([]WaitlistOffer, error) {
+	rows, err := db.Query(`SELECT `+waitlistOfferColumns+waitlistOfferJoins+`
+		WHERE w.patient_id = ? AND o.status = 'offered' AND o.expires_at > NOW()
+		ORDER BY o.expires_at ASC`, patientID)
+	if err != nil {
+		return nil, err
+	}
+	return scanWaitlistOffers(rows)
+}
+
+// GetExpiredWaitlistOffers - Tawaran yang lewat batas waktu tanpa diklaim
+func GetExpiredWaitlistOffers(db *sql.DB) ([]WaitlistOffer, error) {
+	rows, err := db.Query(`SELECT ` + waitlistOfferColumns + waitlistOfferJoins + `
+		WHERE o.status = 'offered' AND o.expires_at <= NOW()`)
+	if err != nil {
+		return nil, err
+	}
+	return scanWaitlistOffers(rows)
+}
+
+// ExpireWaitlistOffer - Tandai tawaran kedaluwarsa; false jika sudah diklaim/diproses lebih dulu
+func ExpireWaitlistOffer(db *sql.DB, offerID int) (bool, error) {
+	res, err := db.Exec(`UPDATE waitlist_offers SET status = 'expired'
+	                     WHERE offer_id = ? AND status = 'offered'`, offerID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// IsSlotTaken - True jika dokter sudah punya appointment aktif di tanggal & jam tersebut
+func IsSlotTaken(db *sql.DB, doctorID int, tanggal, waktu string) (bool, error) {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM appointments
+	                    WHERE doctor_id = ? AND tanggal_konsultasi = ? AND waktu_konsultasi = ?
+	                      AND status IN ('approved', 'in_progress', 'completed')`,
+		doctorID, tanggal, waktu).Scan(&count)
+	return count > 0, err
+}
+
+// ClaimWaitlistOffer - Pasien mengklaim slot: appointment langsung approved dengan dokter & jam slot tersebut.
+// check (aturan kuota) dijalankan setelah tanggal & pasien dikunci, lihat lockBookingDate.
+func ClaimWaitlistOffer(db *sql.DB, offer *WaitlistOffer, nomorReg string, check func(tx *sql.Tx) error) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Entri daftar tunggu yang sudah dibatalkan pasien tidak bisa diklaim lewat tawaran yang masih terbuka
+	res, err := tx.Exec(`UPDATE waitlist_offers o
+	                     JOIN waitlist w ON w.waitlist_id = o.waitlist_id
+	                     SET o.status = 'claimed'
+	                     WHERE o.offer_id = ? AND o.status = 'offered' AND o.expires_at > NOW() AND w.status = 'waiting'`,
+		offer.OfferID)
+	if err != nil {
+		return 0, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n != 1 {
+		return 0, ErrOfferUnavailable
+	}
+
+	tanggal := offer.Tanggal.Format("2006-01-02")
+	if err := lockBookingDate(tx, offer.PatientID, tanggal, check); err != nil {
+		return 0, err
+	}
+
+	// Kunci baris appointment dokter di tanggal itu supaya slot tidak terisi dua kali
+	var count int
+	err = tx.QueryRow(`SELECT COUNT(*) FROM appointments
+	                   WHERE doctor_id = ? AND tanggal_konsultasi = ? AND waktu_konsultasi = ?
+	                     AND status IN ('approved', 'in_progress', 'completed')
+	                   FOR UPDATE`, offer.DoctorID, tanggal, offer.Waktu).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	if count > 0 {
+		return 0, ErrOfferUnavailable
+	}
+
+	// Poli appointment mengikuti poli dokter pemilik slot
+	res, err = tx.Exec(`INSERT INTO appointments
+	                    (nomor_registrasi, patient_id, doctor_id, poli_id, tanggal_konsultasi, waktu_konsultasi, status, assigned_at)
+	                    SELECT ?, ?, ?, poli_id, ?, ?, 'approved', NOW() FROM users WHERE user_id = ?`,
+		nomorReg, offer.PatientID, offer.DoctorID, tanggal, offer.Waktu, offer.DoctorID)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec(`UPDATE waitlist_offers SET appointment_id = ? WHERE offer_id = ?`, id, offer.OfferID); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(`UPDATE waitlist SET status = 'claimed' WHERE waitlist_id = ?`, offer.WaitlistID); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	events.Publish(events.AppointmentCreated, int(id))
+	events.Publish(events.AppointmentApproved, int(id))
+	return int(id), nil
+}
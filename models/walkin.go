@@ -0,0 +1,170 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"klinik-app/events"
+	"strings"
+)
+
+// ErrAlreadyBookedToday - Pasien sudah punya appointment aktif hari ini
+var ErrAlreadyBookedToday = errors.New("pasien sudah memiliki appointment aktif hari ini")
+
+// SearchPatients - Cari pasien berdasarkan awalan NIK atau potongan nama (untuk pendaftaran di loket)
+func SearchPatients(db *sql.DB, q string, limit int) ([]User, error) {
+	rows, err := db.Query(`
+		SELECT user_id, nik, nama, created_at, email, no_hp
+		FROM users
+		WHERE role = 'pasien' AND (nik LIKE CONCAT(?, '%') OR nama LIKE CONCAT('%', ?, '%'))
+		ORDER BY nama ASC
+		LIMIT ?
+	`, q, q, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var patients []User
+	for rows.Next() {
+		var u User
+		if err := rows.Scan(&u.UserID, &u.NIK, &u.Nama, &u.CreatedAt, &u.Email, &u.NoHP); err != nil {
+			return nil, err
+		}
+		u.Role = "pasien"
+		patients = append(patients, u)
+	}
+
+	return patients, nil
+}
+
+// ErrInvalidActivationCode - Kode aktivasi akun loket salah atau akun sudah diaktifkan
+var ErrInvalidActivationCode = errors.New("kode aktivasi tidak valid")
+
+// CreateWalkInPatient - Daftarkan pasien baru di loket tanpa password (belum bisa login sampai registrasi online).
+// Kode aktivasi dicetak di tiket dan wajib diisi saat pasien melengkapi akunnya secara online.
+func CreateWalkInPatient(db *sql.DB, nik, nama, email, noHP string) (int, error) {
+	kode, err := newVerificationCode()
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := db.Exec(`INSERT INTO users (nik, nama, password, role, email, no_hp, kode_aktivasi)
+	                     VALUES (?, ?, '', 'pasien', NULLIF(?, ''), NULLIF(?, ''), ?)`, nik, nama, email, noHP, kode)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	return int(id), err
+}
+
+// GetOrCreateActivationCode - Kode aktivasi pasien loket yang belum punya password ("" jika akun sudah aktif);
+// pasien loket lama yang belum punya kode dibuatkan saat tiket dicetak
+func GetOrCreateActivationCode(db *sql.DB, patientID int) (string, error) {
+	var password string
+	var kode sql.NullString
+	err := db.QueryRow(`SELECT password, kode_aktivasi FROM users WHERE user_id = ? AND role = 'pasien'`,
+		patientID).Scan(&password, &kode)
+	if err != nil || password != "" {
+		return "", err
+	}
+	if kode.Valid {
+		return kode.String, nil
+	}
+
+	baru, err := newVerificationCode()
+	if err != nil {
+		return "", err
+	}
+	_, err = db.Exec(`UPDATE users SET kode_aktivasi = ? WHERE user_id = ? AND password = '' AND kode_aktivasi IS NULL`,
+		baru, patientID)
+	if err != nil {
+		return "", err
+	}
+
+	// Jika dua tiket dicetak bersamaan, pakai kode yang tersimpan
+	err = db.QueryRow(`SELECT COALESCE(kode_aktivasi, '') FROM users WHERE user_id = ?`, patientID).Scan(&baru)
+	return baru, err
+}
+
+// ActivateWalkInAccount - Pasien loket melengkapi akunnya dengan kode aktivasi dari tiket; kode hanya berlaku sekali
+func ActivateWalkInAccount(db *sql.DB, patientID int, kode, nama, hashedPassword, email, noHP string) error {
+	res, err := db.Exec(`UPDATE users
+	                     SET nama = ?, password = ?, kode_aktivasi = NULL,
+	                         email = COALESCE(NULLIF(?, ''), email), no_hp = COALESCE(NULLIF(?, ''), no_hp)
+	                     WHERE user_id = ? AND role = 'pasien' AND password = '' AND kode_aktivasi = ?`,
+		nama, hashedPassword, email, noHP, patientID, strings.ToUpper(strings.TrimSpace(kode)))
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n != 1 {
+		return ErrInvalidActivationCode
+	}
+	return nil
+}
+
+// nextQueueNumber - Nomor antrian berikutnya pada tanggal tersebut untuk poli tersebut (dikunci sampai transaksi selesai)
+func nextQueueNumber(tx *sql.Tx, poliID sql.NullInt64, tanggal string) (int, error) {
+	var last int
+	err := tx.QueryRow(`SELECT COALESCE(MAX(nomor_antrian), 0) FROM appointments
+	                    WHERE tanggal_konsultasi = ? AND poli_id <=> ?
+	                    FOR UPDATE`, tanggal, poliID).Scan(&last)
+	return last + 1, err
+}
+
+// CreateWalkInAppointment - Appointment hari ini untuk pasien yang datang langsung: langsung approved & tercatat hadir
+// dengan nomor antrian. Walk-in tidak memakai jam slot dokter (waktu_konsultasi NULL); urutannya mengikuti nomor
+// antrian sehingga tidak bertabrakan dengan jam pasien booking.
+// tanggal (YYYY-MM-DD) adalah hari ini di zona waktu klinik
+func CreateWalkInAppointment(db *sql.DB, nomorReg string, patientID, doctorID int, tanggal string) (int, int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	// Dikunci seperti booking online supaya walk-in ikut terhitung di kuota tanggal yang sama
+	err = lockBookingDate(tx, patientID, tanggal, func(tx *sql.Tx) error {
+		exists, err := HasAppointmentOnDate(tx, patientID, tanggal)
+		if err == nil && exists {
+			return ErrAlreadyBookedToday
+		}
+		return err
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Poli appointment mengikuti poli dokter yang dipilih
+	var poliID sql.NullInt64
+	if err := tx.QueryRow(`SELECT poli_id FROM users WHERE user_id = ? AND role = 'dokter'`, doctorID).Scan(&poliID); err != nil {
+		return 0, 0, err
+	}
+
+	antrian, err := nextQueueNumber(tx, poliID, tanggal)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	res, err := tx.Exec(`INSERT INTO appointments
+	                     (nomor_registrasi, patient_id, doctor_id, poli_id, tanggal_konsultasi, nomor_antrian, status, arrived_at, assigned_at)
+	                     VALUES (?, ?, ?, ?, ?, ?, 'approved', NOW(), NOW())`,
+		nomorReg, patientID, doctorID, poliID, tanggal, antrian)
+	if err != nil {
+		return 0, 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, err
+	}
+
+	events.Publish(events.AppointmentCreated, int(id))
+	events.Publish(events.AppointmentApproved, int(id))
+	return int(id), antrian, nil
+}
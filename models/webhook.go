@@ -0,0 +1,251 @@
+package models
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+type Webhook struct {
+	WebhookID int       `json:"webhook_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"-"`
+	Events    string    `json:"events"` // "*" atau daftar event dipisah koma
+	Aktif     bool      `json:"aktif"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Subscribed - True jika webhook berlangganan jenis event ini
+func (w Webhook) Subscribed(eventType string) bool {
+	for _, e := range strings.Split(w.Events, ",") {
+		e = strings.TrimSpace(e)
+		if e == "*" || e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDelivery struct {
+	DeliveryID    int            `json:"delivery_id"`
+	WebhookID     int            `json:"webhook_id"`
+	EventType     string         `json:"event_type"`
+	Payload       string         `json:"payload"`
+	Status        string         `json:"status"` // pending / success / failed
+	Attempts      int            `json:"attempts"`
+	ResponseCode  sql.NullInt64  `json:"response_code"`
+	LastError     sql.NullString `json:"last_error"`
+	NextAttemptAt time.Time      `json:"next_attempt_at"`
+	CreatedAt     time.Time      `json:"created_at"`
+	DeliveredAt   sql.NullTime   `json:"delivered_at"`
+
+	// Join fields
+	URL    string `json:"url,omitempty"`
+	Secret string `json:"-"`
+}
+
+// newWebhookSecret - Secret acak untuk tanda tangan HMAC
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// CreateWebhook - Admin mendaftarkan URL tujuan; secret dibuat otomatis
+func CreateWebhook(db *sql.DB, url, eventList string) error {
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`INSERT INTO webhooks (url, secret, events) VALUES (?, ?, ?)`, url, secret, eventList)
+	return err
+}
+
+// GetWebhooks - Semua webhook terdaftar
+func GetWebhooks(db *sql.DB) ([]Webhook, error) {
+	rows, err := db.Query(`SELECT webhook_id, url, secret, events, aktif, created_at
+	                       FROM webhooks ORDER BY webhook_id ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []Webhook
+	for rows.Next() {
+		var wh Webhook
+		if err := rows.Scan(&wh.WebhookID, &wh.URL, &wh.Secret, &wh.Events, &wh.Aktif, &wh.CreatedAt); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, wh)
+	}
+
+	return webhooks, nil
+}
+
+// SetWebhookActive - Aktifkan/nonaktifkan webhook
+func SetWebhookActive(db *sql.DB, webhookID int, aktif bool) error {
+	_, err := db.Exec(`UPDATE webhooks SET aktif = ? WHERE webhook_id = ?`, aktif, webhookID)
+	return err
+}
+
+// DeleteWebhook - Hapus webhook beserta log pengirimannya
+func DeleteWebhook(db *sql.DB, webhookID int) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM webhook_deliveries WHERE webhook_id = ?`, webhookID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM webhooks WHERE webhook_id = ?`, webhookID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CreateWebhookDelivery - Catat pengiriman baru; langsung di-lease sebentar karena akan segera dicoba
+func CreateWebhookDelivery(db *sql.DB, webhookID int, eventType, payload string) (int, error) {
+	res, err := db.Exec(`INSERT INTO webhook_deliveries (webhook_id, event_type, payload, next_attempt_at)
+	                     VALUES (?, ?, ?, NOW() + INTERVAL 5 MINUTE)`, webhookID, eventType, payload)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	return int(id), err
+}
+
+const webhookDeliveryColumns = `
+	d.delivery_id, d.webhook_id, d.event_type, d.payload, d.status, d.attempts,
+	d.response_code, d.last_error, d.next_attempt_at, d.created_at, d.delivered_at,
+	w.url, w.secret
+`
+
+func scanWebhookDeliveries(rows *sql.Rows) ([]WebhookDelivery, error) {
+	defer rows.Close()
+
+	var deliveries []WebhookDelivery
+	for rows.Next() {
+		var d WebhookDelivery
+		err := rows.Scan(
+			&d.DeliveryID,
+			&d.WebhookID,
+			&d.EventType,
+			&d.Payload,
+			&d.Status,
+			&d.Attempts,
+			&d.ResponseCode,
+			&d.LastError,
+			&d.NextAttemptAt,
+			&d.CreatedAt,
+			&d.DeliveredAt,
+			&d.URL,
+			&d.Secret,
+		)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// GetWebhookDelivery - Satu pengiriman beserta URL & secret webhook-nya
+func GetWebhookDelivery(db *sql.DB, deliveryID int) (*WebhookDelivery, error) {
+	rows, err := db.Query(`SELECT `+webhookDeliveryColumns+`
+		FROM webhook_deliveries d JOIN webhooks w ON d.webhook_id = w.webhook_id
+		WHERE d.delivery_id = ?`, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := scanWebhookDeliveries(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return &deliveries[0], nil
+}
+
+// GetDueWebhookDeliveries - Pengiriman pending yang sudah waktunya dicoba ulang (webhook masih aktif)
+func GetDueWebhookDeliveries(db *sql.DB, limit int) ([]WebhookDelivery, error) {
+	rows, err := db.Query(`SELECT `+webhookDeliveryColumns+`
+		FROM webhook_deliveries d JOIN webhooks w ON d.webhook_id = w.webhook_id
+		WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND w.aktif = 1
+		ORDER BY d.next_attempt_at ASC
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanWebhookDeliveries(rows)
+}
+
+// GetRecentWebhookDeliveries - Log pengiriman terbaru untuk halaman admin
+func GetRecentWebhookDeliveries(db *sql.DB, limit int) ([]WebhookDelivery, error) {
+	rows, err := db.Query(`SELECT `+webhookDeliveryColumns+`
+		FROM webhook_deliveries d JOIN webhooks w ON d.webhook_id = w.webhook_id
+		ORDER BY d.delivery_id DESC
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanWebhookDeliveries(rows)
+}
+
+// ClaimWebhookDelivery - Lease pengiriman yang jatuh tempo supaya tidak dikirim dobel; false jika sudah diambil
+func ClaimWebhookDelivery(db *sql.DB, deliveryID int) (bool, error) {
+	res, err := db.Exec(`UPDATE webhook_deliveries SET next_attempt_at = NOW() + INTERVAL 5 MINUTE
+	                     WHERE delivery_id = ? AND status = 'pending' AND next_attempt_at <= NOW()`, deliveryID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// MarkWebhookDelivered - Catat pengiriman berhasil
+func MarkWebhookDelivered(db *sql.DB, deliveryID, responseCode int) error {
+	_, err := db.Exec(`UPDATE webhook_deliveries
+	                   SET status = 'success', attempts = attempts + 1, response_code = ?,
+	                       last_error = NULL, delivered_at = NOW()
+	                   WHERE delivery_id = ?`, responseCode, deliveryID)
+	return err
+}
+
+// MarkWebhookFailed - Catat percobaan gagal; retryAfter 0 berarti berhenti mencoba (status failed)
+func MarkWebhookFailed(db *sql.DB, deliveryID, responseCode int, lastError string, retryAfter time.Duration) error {
+	status := "pending"
+	if retryAfter == 0 {
+		status = "failed"
+	}
+	if len(lastError) > 500 {
+		lastError = lastError[:500]
+	}
+
+	_, err := db.Exec(`UPDATE webhook_deliveries
+	                   SET status = ?, attempts = attempts + 1, response_code = NULLIF(?, 0),
+	                       last_error = ?, next_attempt_at = NOW() + INTERVAL ? SECOND
+	                   WHERE delivery_id = ?`,
+		status, responseCode, lastError, int(retryAfter.Seconds()), deliveryID)
+	return err
+}
+
+// RetryWebhookDelivery - Admin menjadwalkan ulang pengiriman yang gagal agar segera dicoba lagi
+func RetryWebhookDelivery(db *sql.DB, deliveryID int) error {
+	_, err := db.Exec(`UPDATE webhook_deliveries SET status = 'pending', next_attempt_at = NOW()
+	                   WHERE delivery_id = ? AND status = 'failed'`, deliveryID)
+	return err
+}
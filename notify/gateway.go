@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// GatewayNotifier - Kirim pesan ke nomor HP lewat HTTP webhook gateway (JSON {"phone": ..., "message": ...})
+type GatewayNotifier struct {
+	Channel string
+	URL     string
+	Token   string
+	Client  *http.Client
+}
+
+// NewWhatsAppFromEnv - Gateway WhatsApp dari env WA_GATEWAY_URL dan WA_GATEWAY_TOKEN
+func NewWhatsAppFromEnv() *GatewayNotifier {
+	return &GatewayNotifier{
+		Channel: "whatsapp",
+		URL:     os.Getenv("WA_GATEWAY_URL"),
+		Token:   os.Getenv("WA_GATEWAY_TOKEN"),
+		Client:  &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// NewSMSFromEnv - Gateway SMS dari env SMS_GATEWAY_URL dan SMS_GATEWAY_TOKEN
+func NewSMSFromEnv() *GatewayNotifier {
+	return &GatewayNotifier{
+		Channel: "sms",
+		URL:     os.Getenv("SMS_GATEWAY_URL"),
+		Token:   os.Getenv("SMS_GATEWAY_TOKEN"),
+		Client:  &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (n *GatewayNotifier) Name() string {
+	return n.Channel
+}
+
+func (n *GatewayNotifier) Send(ctx context.Context, msg Message) error {
+	if msg.To.NoHP == "" {
+		return ErrNoContact
+	}
+
+	payload, err := json.Marshal(map[string]string{
+		"phone":   msg.To.NoHP,
+		"message": msg.Subject + "\n\n" + msg.Body,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", n.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.Token)
+	}
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s gateway: status %d", n.Channel, resp.StatusCode)
+	}
+	return nil
+}
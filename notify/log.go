@@ -0,0 +1,48 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// LogNotifier - Tulis notifikasi ke log server atau ke file (untuk development/audit)
+type LogNotifier struct {
+	Path string // kosong = log server
+
+	mu sync.Mutex
+}
+
+// NewLogFromEnv - LogNotifier dengan file dari env NOTIFY_LOG_FILE (opsional)
+func NewLogFromEnv() *LogNotifier {
+	return &LogNotifier{Path: os.Getenv("NOTIFY_LOG_FILE")}
+}
+
+func (n *LogNotifier) Name() string {
+	return "log"
+}
+
+func (n *LogNotifier) Send(ctx context.Context, msg Message) error {
+	line := fmt.Sprintf("[%s] to=%s (user %d) subject=%q body=%q\n",
+		time.Now().Format(time.RFC3339), msg.To.Nama, msg.To.UserID, msg.Subject, msg.Body)
+
+	if n.Path == "" {
+		log.Print("📨 " + line)
+		return nil
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(line)
+	return err
+}
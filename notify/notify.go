@@ -0,0 +1,78 @@
+// Package notify - Pengiriman notifikasi ke pasien/user lewat berbagai channel (email, WhatsApp, log)
+package notify
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strings"
+)
+
+// ErrNoContact - Penerima tidak punya kontak untuk channel ini (misal belum isi email)
+var ErrNoContact = errors.New("penerima tidak punya kontak untuk channel ini")
+
+type Recipient struct {
+	UserID int
+	Nama   string
+	Email  string
+	NoHP   string
+}
+
+type Message struct {
+	To      Recipient
+	Subject string
+	Body    string
+}
+
+// Notifier - Satu channel pengiriman notifikasi
+type Notifier interface {
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+// Channels - Channel yang aktif, diisi oleh Init
+var Channels []Notifier
+
+// Init - Aktifkan channel dari env NOTIFY_CHANNELS (contoh: "log,smtp,whatsapp,sms"; default "log")
+func Init() {
+	names := os.Getenv("NOTIFY_CHANNELS")
+	if names == "" {
+		names = "log"
+	}
+
+	Channels = nil
+	for _, name := range strings.Split(names, ",") {
+		switch strings.TrimSpace(name) {
+		case "log":
+			Channels = append(Channels, NewLogFromEnv())
+		case "smtp":
+			Channels = append(Channels, NewSMTPFromEnv())
+		case "whatsapp":
+			Channels = append(Channels, NewWhatsAppFromEnv())
+		case "sms":
+			Channels = append(Channels, NewSMSFromEnv())
+		case "":
+		default:
+			log.Printf("⚠️ Unknown notification channel: %s", name)
+		}
+	}
+
+	for _, n := range Channels {
+		log.Println("✓ Notification channel enabled:", n.Name())
+	}
+}
+
+// Send - Kirim pesan ke semua channel aktif kecuali yang dimatikan penerima (disabled[channel] = true)
+func Send(ctx context.Context, msg Message, disabled map[string]bool) {
+	for _, ch := range Channels {
+		if disabled[ch.Name()] {
+			continue
+		}
+
+		err := ch.Send(ctx, msg)
+		if err != nil && !errors.Is(err, ErrNoContact) {
+			log.Printf("❌ Notification via %s to user %d failed: %v", ch.Name(), msg.To.UserID, err)
+		}
+	}
+}
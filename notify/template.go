@@ -0,0 +1,25 @@
+package notify
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+)
+
+// Render - Render file template pesan berisi blok {{define "subject"}} dan {{define "body"}}
+func Render(file string, data interface{}) (subject, body string, err error) {
+	tmpl, err := template.ParseFiles(file)
+	if err != nil {
+		return "", "", err
+	}
+
+	var s, b bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&s, "subject", data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.ExecuteTemplate(&b, "body", data); err != nil {
+		return "", "", err
+	}
+
+	return strings.TrimSpace(s.String()), strings.TrimSpace(b.String()), nil
+}
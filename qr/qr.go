@@ -0,0 +1,393 @@
+// Package qr - Generator QR code sederhana (mode byte, koreksi error level M, versi 1-6) tanpa dependency luar.
+// Cukup untuk teks pendek seperti nomor registrasi; keluaran berupa SVG.
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrTooLong - Teks melebihi kapasitas versi 6-M (106 byte)
+var ErrTooLong = errors.New("qr: teks terlalu panjang")
+
+// blockInfo - Susunan codeword level M untuk versi 1-6: jumlah blok & codeword EC per blok.
+// Pada versi ini semua blok berukuran sama.
+var blockInfo = [...]struct{ total, blocks, ecPerBlock int }{
+	1: {26, 1, 10},
+	2: {44, 1, 16},
+	3: {70, 1, 26},
+	4: {100, 2, 18},
+	5: {134, 2, 24},
+	6: {172, 4, 16},
+}
+
+// Code - Matriks QR; Modules[y][x] true berarti modul gelap
+type Code struct {
+	Size    int
+	Modules [][]bool
+
+	function [][]bool // modul pola tetap (finder, timing, format) yang tidak diisi data & tidak di-mask
+}
+
+// Encode - Buat QR code untuk teks dengan versi terkecil yang cukup
+func Encode(text string) (*Code, error) {
+	data := []byte(text)
+
+	version := 0
+	for v := 1; v < len(blockInfo); v++ {
+		b := blockInfo[v]
+		if 4+8+8*len(data) <= (b.total-b.blocks*b.ecPerBlock)*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrTooLong
+	}
+
+	c := newCode(version)
+	c.drawCodewords(addECC(version, encodeData(version, data)))
+
+	// Pilih mask dengan penalti terkecil
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormatBits(mask)
+		if p := c.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		c.applyMask(mask) // XOR lagi = kembali seperti semula
+	}
+	c.applyMask(best)
+	c.drawFormatBits(best)
+
+	return c, nil
+}
+
+// SVG - Gambar QR code sebagai SVG (termasuk quiet zone 4 modul); ukuran tampilan diatur lewat CSS/atribut
+func (c *Code) SVG() string {
+	const border = 4
+	n := c.Size + 2*border
+
+	var path strings.Builder
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.Modules[y][x] {
+				fmt.Fprintf(&path, "M%d,%dh1v1h-1z", x+border, y+border)
+			}
+		}
+	}
+
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+
+		`<rect width="%d" height="%d" fill="#fff"/><path d="%s" fill="#000"/></svg>`, n, n, n, n, path.String())
+}
+
+// encodeData - Segmen mode byte + terminator + padding sampai kapasitas data versi tersebut
+func encodeData(version int, data []byte) []byte {
+	b := blockInfo[version]
+	capacity := (b.total - b.blocks*b.ecPerBlock) * 8
+
+	var bits []bool
+	put := func(val, n int) {
+		for i := n - 1; i >= 0; i-- {
+			bits = append(bits, val>>i&1 == 1)
+		}
+	}
+
+	put(0x4, 4) // mode byte
+	put(len(data), 8)
+	for _, d := range data {
+		put(int(d), 8)
+	}
+
+	terminator := capacity - len(bits)
+	if terminator > 4 {
+		terminator = 4
+	}
+	put(0, terminator)
+	put(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		put(pad, 8)
+	}
+
+	out := make([]byte, len(bits)/8)
+	for i, bit := range bits {
+		if bit {
+			out[i/8] |= 1 << (7 - uint(i%8))
+		}
+	}
+	return out
+}
+
+// addECC - Bagi data ke blok, tambahkan codeword Reed-Solomon, lalu interleave
+func addECC(version int, data []byte) []byte {
+	b := blockInfo[version]
+	dataPerBlock := len(data) / b.blocks
+	divisor := rsDivisor(b.ecPerBlock)
+
+	var blocks [][]byte
+	for i := 0; i < b.blocks; i++ {
+		block := append([]byte(nil), data[i*dataPerBlock:(i+1)*dataPerBlock]...)
+		blocks = append(blocks, append(block, rsRemainder(block, divisor)...))
+	}
+
+	var out []byte
+	for i := 0; i < len(blocks[0]); i++ {
+		for _, block := range blocks {
+			out = append(out, block[i])
+		}
+	}
+	return out
+}
+
+func newCode(version int) *Code {
+	size := version*4 + 17
+	c := &Code{Size: size}
+	c.Modules = make([][]bool, size)
+	c.function = make([][]bool, size)
+	for i := range c.Modules {
+		c.Modules[i] = make([]bool, size)
+		c.function[i] = make([]bool, size)
+	}
+
+	// Timing pattern
+	for i := 0; i < size; i++ {
+		c.set(6, i, i%2 == 0)
+		c.set(i, 6, i%2 == 0)
+	}
+
+	// Finder pattern di tiga sudut (beserta separator)
+	for _, p := range [][2]int{{3, 3}, {size - 4, 3}, {3, size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := p[0]+dx, p[1]+dy
+				if x >= 0 && x < size && y >= 0 && y < size {
+					dist := max(abs(dx), abs(dy))
+					c.set(x, y, dist != 2 && dist != 4)
+				}
+			}
+		}
+	}
+
+	// Versi 2-6 hanya punya satu alignment pattern di pojok kanan bawah
+	if version > 1 {
+		pos := size - 7
+		for dy := -2; dy <= 2; dy++ {
+			for dx := -2; dx <= 2; dx++ {
+				c.set(pos+dx, pos+dy, max(abs(dx), abs(dy)) != 1)
+			}
+		}
+	}
+
+	c.drawFormatBits(0) // tandai area format; nilainya ditimpa setelah mask dipilih
+	return c
+}
+
+func (c *Code) set(x, y int, dark bool) {
+	c.Modules[y][x] = dark
+	c.function[y][x] = true
+}
+
+// drawFormatBits - Level M + nomor mask, dengan kode BCH, di dua lokasi format
+func (c *Code) drawFormatBits(mask int) {
+	data := 0<<3 | mask // level M = 00
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+
+	size := c.Size
+	for i := 0; i <= 5; i++ {
+		c.set(8, i, bit(i))
+	}
+	c.set(8, 7, bit(6))
+	c.set(8, 8, bit(7))
+	c.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.set(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		c.set(size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.set(8, size-15+i, bit(i))
+	}
+	c.set(8, size-8, true) // dark module
+}
+
+// drawCodewords - Isi codeword secara zigzag dua kolom dari kanan bawah, melewati kolom timing
+func (c *Code) drawCodewords(data []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < c.Size; vert++ {
+			y := vert
+			if upward {
+				y = c.Size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if !c.function[y][x] && i < len(data)*8 {
+					c.Modules[y][x] = data[i/8]>>(7-uint(i%8))&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !c.function[y][x] {
+				c.Modules[y][x] = !c.Modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty - Skor penalti standar QR (deretan warna sama, blok 2x2, pola mirip finder, keseimbangan gelap/terang)
+func (c *Code) penalty() int {
+	size := c.Size
+	at := func(x, y int, transpose bool) bool {
+		if transpose {
+			return c.Modules[x][y]
+		}
+		return c.Modules[y][x]
+	}
+
+	result := 0
+	finderLike := []bool{true, false, true, true, true, false, true}
+	for _, transpose := range []bool{false, true} {
+		for y := 0; y < size; y++ {
+			run := 1
+			for x := 1; x <= size; x++ {
+				if x < size && at(x, y, transpose) == at(x-1, y, transpose) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					result += 3 + run - 5
+				}
+				run = 1
+			}
+
+			// 1:1:3:1:1 dengan 4 modul terang di salah satu sisi
+			for x := 0; x+7 <= size; x++ {
+				match := true
+				for k, dark := range finderLike {
+					if at(x+k, y, transpose) != dark {
+						match = false
+						break
+					}
+				}
+				if match && (lightRun(at, x-4, x, y, transpose, size) || lightRun(at, x+7, x+11, y, transpose, size)) {
+					result += 40
+				}
+			}
+		}
+	}
+
+	dark := 0
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if c.Modules[y][x] {
+				dark++
+			}
+			if x+1 < size && y+1 < size {
+				v := c.Modules[y][x]
+				if c.Modules[y][x+1] == v && c.Modules[y+1][x] == v && c.Modules[y+1][x+1] == v {
+					result += 3
+				}
+			}
+		}
+	}
+
+	total := size * size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	return result + k*10
+}
+
+// lightRun - Modul [from, to) pada baris/kolom tersebut terang semua (di luar matriks dianggap terang)
+func lightRun(at func(x, y int, transpose bool) bool, from, to, y int, transpose bool, size int) bool {
+	for x := from; x < to; x++ {
+		if x >= 0 && x < size && at(x, y, transpose) {
+			return false
+		}
+	}
+	return true
+}
+
+// rsDivisor - Polinom generator Reed-Solomon berderajat n di GF(256)
+func rsDivisor(n int) []byte {
+	result := make([]byte, n)
+	result[n-1] = 1
+	root := byte(1)
+	for i := 0; i < n; i++ {
+		for j := range result {
+			result[j] = gfMul(result[j], root)
+			if j+1 < n {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMul(root, 0x02)
+	}
+	return result
+}
+
+// rsRemainder - Codeword koreksi error untuk data
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i := range result {
+			result[i] ^= gfMul(divisor[i], factor)
+		}
+	}
+	return result
+}
+
+// gfMul - Perkalian di GF(2^8) dengan polinom 0x11D
+func gfMul(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11D
+		z ^= int(y>>uint(i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,249 @@
+package qr
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestEncodeData(t *testing.T) {
+	tests := []struct {
+		version int
+		text    string
+		want    string
+	}{
+		{1, "hi", "40268690ec11ec11ec11ec11ec11ec11"},
+		{2, "KLN-20261019-00017", "4124b4c4e2d32303236313031392d30303031370ec11ec11ec11ec11"},
+	}
+
+	for _, tt := range tests {
+		got := encodeData(tt.version, []byte(tt.text))
+		if hex.EncodeToString(got) != tt.want {
+			t.Errorf("encodeData(%d, %q) = %x, want %s", tt.version, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestRSRemainder(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		ec   int
+		want string
+	}{
+		// Contoh 1-M "HELLO WORLD" (mode alfanumerik) dari tutorial QR yang umum dipakai
+		{"1-M HELLO WORLD", "205b0b78d172dc4d4340ec11ec11ec11", 10, "c4232777ebd7e7e25d17"},
+		{"1-M hi", "40268690ec11ec11ec11ec11ec11ec11", 10, "11a04dc1799b0585f5da"},
+		{"2-M nomor registrasi", "4124b4c4e2d32303236313031392d30303031370ec11ec11ec11ec11", 16,
+			"396bd637b70a0a3592ac1485c2aeb327"},
+	}
+
+	for _, tt := range tests {
+		got := rsRemainder(mustHex(t, tt.data), rsDivisor(tt.ec))
+		if hex.EncodeToString(got) != tt.want {
+			t.Errorf("%s: rsRemainder = %x, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFormatBits(t *testing.T) {
+	// Tabel format information level M (bit 14 di kiri) dari spesifikasi QR
+	want := []string{
+		"101010000010010",
+		"101000100100101",
+		"101111001111100",
+		"101101101001011",
+		"100010111111001",
+		"100000011001110",
+		"100111110010111",
+		"100101010100000",
+	}
+
+	for _, version := range []int{1, 2} {
+		for mask, w := range want {
+			c := newCode(version)
+			c.drawFormatBits(mask)
+			size := c.Size
+
+			// Salinan pertama di sekitar finder kiri atas, salinan kedua terbelah di kanan atas & kiri bawah
+			var first, second int
+			for i := 0; i < 15; i++ {
+				var x1, y1, x2, y2 int
+				switch {
+				case i <= 5:
+					x1, y1 = 8, i
+				case i == 6:
+					x1, y1 = 8, 7
+				case i == 7:
+					x1, y1 = 8, 8
+				case i == 8:
+					x1, y1 = 7, 8
+				default:
+					x1, y1 = 14-i, 8
+				}
+				if i < 8 {
+					x2, y2 = size-1-i, 8
+				} else {
+					x2, y2 = 8, size-15+i
+				}
+				if c.Modules[y1][x1] {
+					first |= 1 << uint(i)
+				}
+				if c.Modules[y2][x2] {
+					second |= 1 << uint(i)
+				}
+			}
+
+			got := strconv.FormatInt(int64(first), 2)
+			got = strings.Repeat("0", 15-len(got)) + got
+			if got != w {
+				t.Errorf("version %d mask %d: format bits = %s, want %s", version, mask, got, w)
+			}
+			if second != first {
+				t.Errorf("version %d mask %d: second copy %015b differs from first %015b", version, mask, second, first)
+			}
+			if !c.Modules[size-8][8] {
+				t.Errorf("version %d mask %d: dark module missing", version, mask)
+			}
+		}
+	}
+}
+
+// goldenCases - Matriks acuan di testdata dibuat oleh encoder referensi (lihat testdata/golden.js)
+var goldenCases = []struct {
+	name    string
+	version int
+	text    string
+}{
+	{"hi", 1, "hi"},
+	{"nomor_registrasi", 2, "KLN-20261019-00017"},
+	{"versi5", 5, "https://klinik.example.com/checkin/KLN-20261019-00017?token=abcdef"},
+	{"versi6", 6, strings.Repeat("x", 106)},
+}
+
+// readGolden - Matriks acuan per mask dari testdata/<name>.golden
+func readGolden(t *testing.T, name string) map[int][]string {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", name+".golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden := map[int][]string{}
+	mask := -1
+	for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+		if strings.HasPrefix(line, "mask ") {
+			mask, err = strconv.Atoi(strings.TrimPrefix(line, "mask "))
+			if err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		golden[mask] = append(golden[mask], line)
+	}
+	if len(golden) != 8 {
+		t.Fatalf("%s.golden: %d masks, want 8", name, len(golden))
+	}
+	return golden
+}
+
+// matrixRows - Matriks sebagai baris teks dengan format yang sama seperti file golden
+func matrixRows(c *Code) []string {
+	rows := make([]string, c.Size)
+	for y := range rows {
+		var b strings.Builder
+		for x := 0; x < c.Size; x++ {
+			if c.Modules[y][x] {
+				b.WriteByte('#')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		rows[y] = b.String()
+	}
+	return rows
+}
+
+// diffRows - Baris pertama yang berbeda (-1 jika sama)
+func diffRows(got, want []string) int {
+	for y := range want {
+		if y >= len(got) || got[y] != want[y] {
+			return y
+		}
+	}
+	if len(got) != len(want) {
+		return len(want)
+	}
+	return -1
+}
+
+func TestMatrix(t *testing.T) {
+	for _, tt := range goldenCases {
+		golden := readGolden(t, tt.name)
+
+		for mask := 0; mask < 8; mask++ {
+			c := newCode(tt.version)
+			c.drawCodewords(addECC(tt.version, encodeData(tt.version, []byte(tt.text))))
+			c.applyMask(mask)
+			c.drawFormatBits(mask)
+
+			got := matrixRows(c)
+			if y := diffRows(got, golden[mask]); y >= 0 {
+				t.Errorf("%s mask %d: row %d differs from reference encoder\n got %s\nwant %s",
+					tt.name, mask, y, rowAt(got, y), rowAt(golden[mask], y))
+			}
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	for _, tt := range goldenCases {
+		golden := readGolden(t, tt.name)
+
+		c, err := Encode(tt.text)
+		if err != nil {
+			t.Fatalf("Encode(%q): %v", tt.text, err)
+		}
+		if want := tt.version*4 + 17; c.Size != want {
+			t.Fatalf("Encode(%q): size %d, want %d (version %d)", tt.text, c.Size, want, tt.version)
+		}
+
+		// Mask dipilih lewat penalti; hasil akhirnya harus sama persis dengan salah satu matriks acuan
+		got := matrixRows(c)
+		found := false
+		for mask := 0; mask < 8; mask++ {
+			found = found || diffRows(got, golden[mask]) < 0
+		}
+		if !found {
+			t.Errorf("Encode(%q): matrix matches no reference mask", tt.text)
+		}
+	}
+}
+
+func rowAt(rows []string, y int) string {
+	if y < len(rows) {
+		return rows[y]
+	}
+	return "(tidak ada)"
+}
+
+func TestEncodeTooLong(t *testing.T) {
+	if _, err := Encode(strings.Repeat("x", 106)); err != nil {
+		t.Errorf("106 byte: %v, want nil", err)
+	}
+	if _, err := Encode(strings.Repeat("x", 107)); err != ErrTooLong {
+		t.Errorf("107 byte: %v, want ErrTooLong", err)
+	}
+}
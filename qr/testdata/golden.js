@@ -0,0 +1,39 @@
+// Membuat matriks acuan untuk qr_test.go dengan encoder QR referensi Kazuhiko Arase
+// (qrcode-terminal/vendor/QRCode, ikut terpasang bersama npm), supaya hasil paket qr dibandingkan
+// dengan encoder lain dan bukan dengan dirinya sendiri.
+//
+//   node qr/testdata/golden.js /usr/lib/node_modules/npm/node_modules/qrcode-terminal/vendor/QRCode
+//
+// Tiap file <nama>.golden berisi matriks untuk mask 0-7 (baris "mask N" lalu satu baris teks per baris modul,
+// '#' gelap dan '.' terang).
+var fs = require('fs');
+var path = require('path');
+
+var lib = process.argv[2];
+var QRCode = require(lib);
+var level = require(path.join(lib, 'QRErrorCorrectLevel')).M;
+
+var cases = [
+  ['hi', 1, 'hi'],
+  ['nomor_registrasi', 2, 'KLN-20261019-00017'],
+  ['versi5', 5, 'https://klinik.example.com/checkin/KLN-20261019-00017?token=abcdef'],
+  ['versi6', 6, new Array(107).join('x')],
+];
+
+cases.forEach(function (c) {
+  var qr = new QRCode(c[1], level);
+  qr.addData(c[2]);
+
+  var out = '';
+  for (var mask = 0; mask < 8; mask++) {
+    qr.makeImpl(false, mask);
+    out += 'mask ' + mask + '\n';
+    for (var y = 0; y < qr.getModuleCount(); y++) {
+      for (var x = 0; x < qr.getModuleCount(); x++) {
+        out += qr.isDark(y, x) ? '#' : '.';
+      }
+      out += '\n';
+    }
+  }
+  fs.writeFileSync(path.join(__dirname, c[0] + '.golden'), out);
+});
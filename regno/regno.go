@@ -0,0 +1,132 @@
+// Package regno - Nomor registrasi appointment yang mudah dibaca, misal KLN-20261018-00427,
+// dengan digit cek supaya salah ketik di loket bisa langsung ketahuan
+package regno
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultFormat - Format bawaan: prefix, tanggal dibuat, urutan harian 4 digit lalu digit cek
+const DefaultFormat = "KLN-{YYYY}{MM}{DD}-{SEQ}{CHECK}"
+
+var (
+	// ErrFormat - Nomor tidak sesuai format (mungkin nomor lama atau baru sebagian)
+	ErrFormat = errors.New("nomor registrasi tidak sesuai format")
+
+	// ErrCheckDigit - Format benar tapi digit cek tidak cocok, hampir pasti salah ketik
+	ErrCheckDigit = errors.New("digit cek nomor registrasi tidak cocok, periksa kembali nomornya")
+)
+
+var placeholder = regexp.MustCompile(`\{(YYYY|YY|MM|DD|SEQ|CHECK)\}`)
+
+// ValidFormat - Format harus memuat {SEQ} dan {CHECK} masing-masing tepat satu kali, serta tanggal lengkap
+// ({YYYY} atau {YY}, {MM}, {DD}) karena urutan dimulai ulang setiap hari
+func ValidFormat(format string) bool {
+	return strings.Count(format, "{SEQ}") == 1 && strings.Count(format, "{CHECK}") == 1 &&
+		strings.Contains(format, "{MM}") && strings.Contains(format, "{DD}") &&
+		(strings.Contains(format, "{YYYY}") || strings.Contains(format, "{YY}"))
+}
+
+// Format - Susun nomor registrasi dari format, tanggal dibuat dan urutan harian
+func Format(format string, t time.Time, seq int) string {
+	nomor := placeholder.ReplaceAllStringFunc(format, func(p string) string {
+		switch p {
+		case "{YYYY}":
+			return t.Format("2006")
+		case "{YY}":
+			return t.Format("06")
+		case "{MM}":
+			return t.Format("01")
+		case "{DD}":
+			return t.Format("02")
+		case "{SEQ}":
+			return fmt.Sprintf("%04d", seq)
+		}
+		return "\x00" // diganti digit cek setelah semua digit lain diketahui
+	})
+
+	nomor = strings.Replace(nomor, "\x00", string(rune('0'+damm(digits(nomor)))), 1)
+	return strings.ToUpper(nomor)
+}
+
+// Verify - Cek nomor yang diketik petugas: ErrFormat jika tidak sesuai format, ErrCheckDigit jika salah ketik
+func Verify(format, nomor string) error {
+	re := pattern(format)
+	m := re.FindStringSubmatchIndex(nomor)
+	if m == nil {
+		return ErrFormat
+	}
+
+	i := re.SubexpIndex("check")
+	start, end := m[2*i], m[2*i+1]
+	if damm(digits(nomor[:start]+nomor[end:])) != int(nomor[start]-'0') {
+		return ErrCheckDigit
+	}
+	return nil
+}
+
+// Normalize - Rapikan input petugas/hasil scan: spasi dibuang, huruf kapital
+func Normalize(nomor string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(nomor), ""))
+}
+
+// pattern - Regex untuk seluruh nomor menurut format, dengan grup "check" untuk digit cek
+func pattern(format string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	last := 0
+	for _, loc := range placeholder.FindAllStringIndex(format, -1) {
+		b.WriteString(regexp.QuoteMeta(strings.ToUpper(format[last:loc[0]])))
+		switch format[loc[0]:loc[1]] {
+		case "{YYYY}":
+			b.WriteString(`\d{4}`)
+		case "{YY}", "{MM}", "{DD}":
+			b.WriteString(`\d{2}`)
+		case "{SEQ}":
+			b.WriteString(`\d{4,}`)
+		case "{CHECK}":
+			b.WriteString(`(?P<check>\d)`)
+		}
+		last = loc[1]
+	}
+	b.WriteString(regexp.QuoteMeta(strings.ToUpper(format[last:])))
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+func digits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// dammTable - Quasigroup algoritma Damm: mendeteksi semua salah ketik satu digit dan
+// semua pertukaran dua digit yang bersebelahan
+var dammTable = [10][10]int{
+	{0, 3, 1, 7, 5, 9, 8, 6, 4, 2},
+	{7, 0, 9, 2, 1, 5, 4, 8, 6, 3},
+	{4, 2, 0, 6, 8, 7, 1, 3, 5, 9},
+	{1, 7, 5, 0, 9, 8, 3, 4, 2, 6},
+	{6, 1, 2, 3, 0, 4, 5, 9, 7, 8},
+	{3, 6, 7, 4, 2, 0, 9, 5, 8, 1},
+	{5, 8, 6, 9, 7, 2, 0, 1, 3, 4},
+	{8, 9, 4, 5, 3, 6, 2, 0, 1, 7},
+	{9, 4, 3, 8, 6, 1, 7, 2, 0, 5},
+	{2, 5, 8, 1, 4, 3, 6, 7, 9, 0},
+}
+
+func damm(digits string) int {
+	interim := 0
+	for _, r := range digits {
+		interim = dammTable[interim][r-'0']
+	}
+	return interim
+}
@@ -0,0 +1,57 @@
+package regno
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormat(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 00:30 WIB tanggal 19 = 17:30 UTC tanggal 18; nomor harus memakai tanggal klinik
+	tengahMalam := time.Date(2026, 10, 18, 17, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		format string
+		t      time.Time
+		seq    int
+		want   string
+	}{
+		{DefaultFormat, tengahMalam.In(loc), 1, "KLN-20261019-00017"},
+		{DefaultFormat, tengahMalam.In(loc), 17, "KLN-20261019-00171"},
+		{DefaultFormat, tengahMalam, 1, "KLN-20261018-00014"}, // waktu UTC memberi tanggal kemarin
+		{"kln/{YY}{MM}{DD}/{SEQ}-{CHECK}", tengahMalam.In(loc), 1, "KLN/261019/0001-3"},
+	}
+
+	for _, tt := range tests {
+		got := Format(tt.format, tt.t, tt.seq)
+		if got != tt.want {
+			t.Errorf("Format(%q, %s, %d) = %s, want %s", tt.format, tt.t, tt.seq, got, tt.want)
+		}
+		if err := Verify(tt.format, got); err != nil {
+			t.Errorf("Verify(%q, %s) = %v, want nil", tt.format, got, err)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		nomor string
+		want  error
+	}{
+		{"KLN-20261019-00017", nil},
+		{"KLN-20261019-00018", ErrCheckDigit}, // digit cek salah
+		{"KLN-20261019-00107", ErrCheckDigit}, // dua digit bertukar
+		{"KLN-20261091-00017", ErrCheckDigit},
+		{"KLN-20261019-0001", ErrFormat},
+		{"REG-12-20261019083000", ErrFormat}, // nomor lama
+	}
+
+	for _, tt := range tests {
+		if got := Verify(DefaultFormat, tt.nomor); got != tt.want {
+			t.Errorf("Verify(%s) = %v, want %v", tt.nomor, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package scheduler
+
+import (
+	"context"
+	"klinik-app/config"
+	"klinik-app/models"
+	"log"
+	"time"
+)
+
+// NoShowJob - Tandai appointment approved dari hari-hari sebelumnya sebagai no_show (cek tiap jam,
+// sehingga appointment kemarin diproses segera setelah tengah malam)
+func NoShowJob() Job {
+	return Job{
+		Name:     "no-show-sweep",
+		Interval: time.Hour,
+		Run:      markNoShows,
+	}
+}
+
+func markNoShows(ctx context.Context) error {
+	n, err := models.MarkPastNoShows(config.DB, config.Now().Format("2006-01-02"))
+	if n > 0 {
+		log.Printf("✓ %d past appointment(s) marked as no-show", n)
+	}
+	return err
+}
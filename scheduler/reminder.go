@@ -0,0 +1,132 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"klinik-app/config"
+	"klinik-app/models"
+	"klinik-app/notify"
+	"log"
+	"sort"
+	"time"
+)
+
+// ReminderJob - Kirim pengingat jadwal konsultasi sesuai REMINDER_OFFSETS (cek tiap menit)
+func ReminderJob() Job {
+	return Job{
+		Name:     "appointment-reminder",
+		Interval: time.Minute,
+		Run:      sendReminders,
+	}
+}
+
+// offsetLabel - Nama pengingat, misal 24h -> "H-1", 2h -> "2 jam", 30m -> "30 menit"
+func offsetLabel(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("H-%d", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%d jam", d/time.Hour)
+	default:
+		return fmt.Sprintf("%d menit", d/time.Minute)
+	}
+}
+
+func sendReminders(ctx context.Context) error {
+	offsets := config.ReminderOffsets()
+	if len(offsets) == 0 || len(notify.Channels) == 0 {
+		return nil
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+
+	loc := config.Location()
+	now := config.Now()
+	until := now.Add(offsets[len(offsets)-1])
+
+	appointments, err := models.GetApprovedAppointmentsBetween(config.DB,
+		now.Format("2006-01-02"), until.Format("2006-01-02"))
+	if err != nil {
+		return err
+	}
+
+	for _, apt := range appointments {
+		start, ok := apt.JadwalMulai(loc)
+		if !ok || !now.Before(start) {
+			continue
+		}
+
+		// Hanya pengingat terdekat yang sudah jatuh tempo, supaya tidak dobel setelah server mati lama
+		var due time.Duration
+		for _, o := range offsets {
+			if !now.Before(start.Add(-o)) {
+				due = o
+				break
+			}
+		}
+		if due == 0 {
+			continue
+		}
+
+		if err := remind(ctx, apt, start, offsetLabel(due)); err != nil {
+			log.Printf("❌ Reminder for appointment %d failed: %v", apt.AppointmentID, err)
+		}
+	}
+
+	return nil
+}
+
+func remind(ctx context.Context, apt models.Appointment, start time.Time, jenis string) error {
+	pasien, err := models.GetUserByID(config.DB, apt.PatientID)
+	if err != nil {
+		return err
+	}
+
+	msg := notify.Message{
+		To: notify.Recipient{
+			UserID: pasien.UserID,
+			Nama:   pasien.Nama,
+			Email:  pasien.Email.String,
+			NoHP:   pasien.NoHP.String,
+		},
+		Subject: "Pengingat Jadwal Konsultasi - " + config.ClinicName(),
+		Body: fmt.Sprintf("Halo %s,\n\nIni pengingat jadwal konsultasi Anda di %s:\n"+
+			"Tanggal: %s\nWaktu: %s\nDokter: %s\nNo. Registrasi: %s\n\nMohon datang 15 menit lebih awal.",
+			pasien.Nama, config.ClinicName(), start.Format("02/01/2006"), start.Format("15:04"),
+			apt.NamaDokter, apt.NomorRegistrasi),
+	}
+
+	disabled, err := models.GetDisabledChannels(config.DB, pasien.UserID)
+	if err != nil {
+		return err
+	}
+
+	for _, ch := range notify.Channels {
+		if disabled[ch.Name()] {
+			continue
+		}
+
+		claimed, err := models.ClaimReminder(config.DB, apt.AppointmentID, jenis, ch.Name())
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		err = ch.Send(ctx, msg)
+		if errors.Is(err, notify.ErrNoContact) {
+			continue // tetap ditandai supaya tidak dicoba terus
+		}
+		if err != nil {
+			log.Printf("❌ Reminder %s via %s for appointment %d failed: %v", jenis, ch.Name(), apt.AppointmentID, err)
+			if err := models.ReleaseReminder(config.DB, apt.AppointmentID, jenis, ch.Name()); err != nil {
+				return err
+			}
+			continue
+		}
+		log.Printf("✓ Reminder %s sent via %s for appointment %d", jenis, ch.Name(), apt.AppointmentID)
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+package scheduler
+
+import (
+	"klinik-app/waitlist"
+	"time"
+)
+
+// WaitlistJob - Tawaran slot yang kedaluwarsa dialihkan ke antrian berikutnya (cek tiap menit)
+func WaitlistJob() Job {
+	return Job{
+		Name:     "waitlist-offer-expiry",
+		Interval: time.Minute,
+		Run:      waitlist.ExpireOffers,
+	}
+}
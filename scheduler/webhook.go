@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"context"
+	"klinik-app/config"
+	"klinik-app/models"
+	"klinik-app/webhook"
+	"log"
+	"time"
+)
+
+// WebhookRetryJob - Coba ulang pengiriman webhook yang gagal sesuai jadwal backoff (cek tiap 30 detik)
+func WebhookRetryJob() Job {
+	return Job{
+		Name:     "webhook-retry",
+		Interval: 30 * time.Second,
+		Run:      retryWebhooks,
+	}
+}
+
+func retryWebhooks(ctx context.Context) error {
+	deliveries, err := models.GetDueWebhookDeliveries(config.DB, 50)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range deliveries {
+		claimed, err := models.ClaimWebhookDelivery(config.DB, d.DeliveryID)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := webhook.Deliver(ctx, d); err != nil {
+			log.Printf("❌ %v", err)
+			continue
+		}
+		log.Printf("✓ Webhook delivery %d sent after %d attempt(s)", d.DeliveryID, d.Attempts+1)
+	}
+
+	return nil
+}
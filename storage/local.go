@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStorage - Simpan file di filesystem lokal di bawah folder Root
+type LocalStorage struct {
+	Root string
+}
+
+// NewLocal - Buat LocalStorage dengan folder root tertentu
+func NewLocal(root string) *LocalStorage {
+	return &LocalStorage{Root: root}
+}
+
+// path - Ubah key menjadi path di disk, tolak key yang keluar dari Root
+func (s *LocalStorage) path(key string) (string, error) {
+	if key == "" || strings.Contains(key, "..") {
+		return "", errors.New("key file tidak valid")
+	}
+	return filepath.Join(s.Root, filepath.FromSlash(key)), nil
+}
+
+// Save - Tulis isi reader ke file (folder dibuat otomatis)
+func (s *LocalStorage) Save(key string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	dst, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(dst, r); err != nil {
+		dst.Close()
+		os.Remove(path)
+		return err
+	}
+
+	return dst.Close()
+}
+
+// Open - Buka file untuk dibaca
+func (s *LocalStorage) Open(key string) (io.ReadSeekCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// Delete - Hapus file (tidak error jika file sudah tidak ada)
+func (s *LocalStorage) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+var (
+	ErrTooLarge       = errors.New("ukuran file melebihi batas")
+	ErrTypeNotAllowed = errors.New("jenis file tidak diizinkan")
+)
+
+// Storage - Backend penyimpanan file; key berupa path relatif seperti "lampiran/12/abc.pdf"
+type Storage interface {
+	Save(key string, r io.Reader) error
+	Open(key string) (io.ReadSeekCloser, error)
+	Delete(key string) error
+}
+
+// DocumentTypes - Jenis file dokumen medis yang boleh diupload (MIME -> ekstensi)
+var DocumentTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+// Validate - Cek ukuran dan jenis file berdasarkan isi (bukan nama file), kembalikan MIME & ekstensi
+func Validate(file multipart.File, header *multipart.FileHeader, maxSize int64, allowed map[string]string) (string, string, error) {
+	if header.Size > maxSize {
+		return "", "", ErrTooLarge
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", "", err
+	}
+
+	mimeType := http.DetectContentType(head[:n])
+	ext, ok := allowed[mimeType]
+	if !ok {
+		return "", "", ErrTypeNotAllowed
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", "", err
+	}
+
+	return mimeType, ext, nil
+}
@@ -0,0 +1,128 @@
+// Package waitlist - Tawarkan slot dokter yang dibatalkan ke pasien di daftar tunggu
+package waitlist
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"klinik-app/config"
+	"klinik-app/models"
+	"klinik-app/notify"
+	"log"
+	"time"
+)
+
+// OfferSlot - Tawarkan slot appointment yang dibatalkan ke antrian waitlist berikutnya (jika ada)
+func OfferSlot(ctx context.Context, sourceAppointmentID int) error {
+	apt, err := models.GetAppointmentByID(config.DB, sourceAppointmentID)
+	if err != nil {
+		return err
+	}
+
+	// Hanya appointment yang sudah punya dokter & jam yang membebaskan slot
+	start, ok := apt.JadwalMulai(config.Location())
+	if !ok || !apt.DoctorID.Valid {
+		return nil
+	}
+
+	ttl := config.WaitlistClaimTTL()
+	if until := time.Until(start); until < ttl {
+		ttl = until
+	}
+	if ttl < 5*time.Minute {
+		return nil // terlalu mepet untuk ditawarkan
+	}
+
+	tanggal := apt.TanggalKonsultasi.Format("2006-01-02")
+	doctorID := int(apt.DoctorID.Int64)
+
+	entry, err := models.NextWaitlistEntry(config.DB, sourceAppointmentID, doctorID, tanggal)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := models.CreateWaitlistOffer(config.DB, entry.WaitlistID, sourceAppointmentID, doctorID,
+		tanggal, apt.WaktuKonsultasi.String, ttl)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("✓ Waitlist %d offered slot of appointment %d", entry.WaitlistID, sourceAppointmentID)
+	return notifyOffer(ctx, entry.PatientID, apt, start, token, ttl)
+}
+
+func notifyOffer(ctx context.Context, patientID int, apt *models.Appointment, start time.Time, token string, ttl time.Duration) error {
+	pasien, err := models.GetUserByID(config.DB, patientID)
+	if err != nil {
+		return err
+	}
+
+	link := "/pasien/waitlist/klaim/" + token
+	batas := config.Now().Add(ttl)
+
+	data := map[string]interface{}{
+		"Nama":    pasien.Nama,
+		"Klinik":  config.ClinicName(),
+		"Tanggal": start.Format("02/01/2006"),
+		"Waktu":   start.Format("15:04"),
+		"Dokter":  apt.NamaDokter,
+		"Batas":   batas.Format("02/01/2006 15:04"),
+		"URL":     config.BaseURL() + link,
+	}
+
+	err = models.CreateNotification(config.DB, pasien.UserID, "Slot konsultasi tersedia",
+		fmt.Sprintf("Slot %s pukul %s dengan %s tersedia untuk Anda. Klaim sebelum %s.",
+			data["Tanggal"], data["Waktu"], apt.NamaDokter, data["Batas"]), link)
+	if err != nil {
+		return err
+	}
+
+	disabled, err := models.GetDisabledChannels(config.DB, pasien.UserID)
+	if err != nil {
+		return err
+	}
+
+	subject, body, err := notify.Render("templates/notifikasi/waitlist_offer.txt", data)
+	if err != nil {
+		return err
+	}
+
+	notify.Send(ctx, notify.Message{
+		To: notify.Recipient{
+			UserID: pasien.UserID,
+			Nama:   pasien.Nama,
+			Email:  pasien.Email.String,
+			NoHP:   pasien.NoHP.String,
+		},
+		Subject: subject,
+		Body:    body,
+	}, disabled)
+	return nil
+}
+
+// ExpireOffers - Tutup tawaran yang tidak diklaim lalu tawarkan slotnya ke antrian berikutnya
+func ExpireOffers(ctx context.Context) error {
+	offers, err := models.GetExpiredWaitlistOffers(config.DB)
+	if err != nil {
+		return err
+	}
+
+	for _, o := range offers {
+		expired, err := models.ExpireWaitlistOffer(config.DB, o.OfferID)
+		if err != nil {
+			return err
+		}
+		if !expired {
+			continue
+		}
+
+		if err := OfferSlot(ctx, o.SourceAppointmentID); err != nil {
+			log.Printf("❌ Waitlist fallback for appointment %d: %v", o.SourceAppointmentID, err)
+		}
+	}
+
+	return nil
+}
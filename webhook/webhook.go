@@ -0,0 +1,87 @@
+// Package webhook - Pengiriman event ke URL eksternal dengan tanda tangan HMAC dan retry bertahap
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"klinik-app/config"
+	"klinik-app/models"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// backoff - Jeda sebelum percobaan ulang ke-n; setelah habis pengiriman ditandai failed
+var backoff = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+	6 * time.Hour,
+}
+
+// MaxAttempts - Jumlah percobaan maksimal per pengiriman
+var MaxAttempts = len(backoff) + 1
+
+var client = &http.Client{Timeout: 10 * time.Second}
+
+// Sign - Tanda tangan HMAC-SHA256 (hex) atas "<timestamp>.<body>".
+// Penerima menghitung ulang dengan secret yang sama dan membandingkan dengan header X-Klinik-Signature.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Deliver - Kirim satu pengiriman lalu catat hasilnya (sukses, jadwal retry, atau failed)
+func Deliver(ctx context.Context, d models.WebhookDelivery) error {
+	code, sendErr := send(ctx, d)
+	if sendErr == nil {
+		return models.MarkWebhookDelivered(config.DB, d.DeliveryID, code)
+	}
+
+	var retryAfter time.Duration
+	if d.Attempts < len(backoff) {
+		retryAfter = backoff[d.Attempts]
+	}
+
+	if err := models.MarkWebhookFailed(config.DB, d.DeliveryID, code, sendErr.Error(), retryAfter); err != nil {
+		return err
+	}
+	return fmt.Errorf("webhook %d delivery %d attempt %d: %w", d.WebhookID, d.DeliveryID, d.Attempts+1, sendErr)
+}
+
+func send(ctx context.Context, d models.WebhookDelivery) (int, error) {
+	body := []byte(d.Payload)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", d.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "klinik-app-webhook/1.0")
+	req.Header.Set("X-Klinik-Event", d.EventType)
+	req.Header.Set("X-Klinik-Delivery", strconv.Itoa(d.DeliveryID))
+	req.Header.Set("X-Klinik-Timestamp", timestamp)
+	req.Header.Set("X-Klinik-Signature", "sha256="+Sign(d.Secret, timestamp, body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}